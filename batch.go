@@ -0,0 +1,110 @@
+package mlkem
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AlexanderYastrebov/mlkem/internal"
+)
+
+// KeyGenBatch generates n key pairs on up to GOMAXPROCS goroutines.
+//
+// The 64-byte d‖z seeds of all keys are read from rand up front, in order,
+// so the i-th key pair is the one [ParameterSet.KeyGenRand] would return for the i-th seed
+// regardless of scheduling.
+// It returns an error if n is negative, if rand fails or does not provide enough bytes,
+// or ctx.Err() if ctx is done before all keys are generated.
+func (p ParameterSet) KeyGenBatch(ctx context.Context, n int, rand io.Reader) ([]EncapsulationKey, []DecapsulationKey, error) {
+	if err := p.checkValid("KeyGenBatch"); err != nil {
+		return nil, nil, err
+	}
+	if n < 0 {
+		return nil, nil, p.newError("KeyGenBatch", "n", ErrInvalidLength)
+	}
+	seeds := make([]byte, n*SeedSize)
+	defer clear(seeds)
+	if _, err := io.ReadFull(rand, seeds); err != nil {
+		return nil, nil, p.entropyError("KeyGenBatch", err)
+	}
+
+	// Keys are carved out of two allocations, capped so that appending to one does not overwrite the next.
+	ekSize, dkSize := p.EncapsulationKeySize(), p.DecapsulationKeySize()
+	ekBuf, dkBuf := make([]byte, n*ekSize), make([]byte, n*dkSize)
+	eks, dks := make([]EncapsulationKey, n), make([]DecapsulationKey, n)
+	err := forEach(ctx, n, func(i int) {
+		seed := seeds[i*SeedSize : (i+1)*SeedSize]
+		dstEK := ekBuf[i*ekSize : i*ekSize : (i+1)*ekSize]
+		dstDK := dkBuf[i*dkSize : i*dkSize : (i+1)*dkSize]
+		eks[i], dks[i] = p.keyGen(dstEK, dstDK, seed[:32], seed[32:])
+	})
+	if err != nil {
+		clear(dkBuf)
+		return nil, nil, err
+	}
+	return eks, dks, nil
+}
+
+// forEach calls f(i) for every i in [0, n) on up to GOMAXPROCS goroutines.
+// It stops handing out indices once ctx is done and returns ctx.Err()
+// unless all calls have completed.
+func forEach(ctx context.Context, n int, f func(i int)) error {
+	var next, done atomic.Int64
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), n) {
+		wg.Go(func() {
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				f(i)
+				done.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+	if done.Load() < int64(n) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// DecapsulateBatch is like calling [ParameterSet.Decaps] with dk for each ciphertext of cs,
+// but validates and expands dk only once and decapsulates on up to GOMAXPROCS goroutines.
+//
+// It returns an error if dk is invalid. Otherwise the i-th shared key and error
+// are the results for cs[i], see [Decapsulator.DecapsulateBatch].
+func (p ParameterSet) DecapsulateBatch(ctx context.Context, dk DecapsulationKey, cs []Ciphertext) ([]SharedKey, []error, error) {
+	if err := p.checkDecapsulationKey("DecapsulateBatch", dk); err != nil {
+		return nil, nil, err
+	}
+	d := &Decapsulator{p: p, dk: internal.NewDecapsulationKey(dk, p.k())}
+	defer d.Destroy()
+	Ks, errs := d.DecapsulateBatch(ctx, cs)
+	return Ks, errs, nil
+}
+
+// DecapsulateBatch decapsulates each ciphertext of cs on up to GOMAXPROCS goroutines.
+//
+// The i-th shared key and error are the results of [Decapsulator.Decapsulate] for cs[i],
+// including the implicit rejection of invalid ciphertexts.
+// Ciphertexts that were not processed because ctx is done have ctx.Err() as their error.
+func (d *Decapsulator) DecapsulateBatch(ctx context.Context, cs []Ciphertext) ([]SharedKey, []error) {
+	n := len(cs)
+	buf := make([]byte, n*SharedKeySize)
+	Ks, errs := make([]SharedKey, n), make([]error, n)
+	err := forEach(ctx, n, func(i int) {
+		Ks[i], errs[i] = d.decapsulateTo("DecapsulateBatch", buf[i*SharedKeySize:i*SharedKeySize:(i+1)*SharedKeySize], cs[i])
+	})
+	if err != nil {
+		for i := range n {
+			if Ks[i] == nil && errs[i] == nil {
+				errs[i] = err
+			}
+		}
+	}
+	return Ks, errs
+}
@@ -0,0 +1,154 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestKeyGenBatch(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			const n = 37
+			seeds := make([]byte, n*mlkem.SeedSize)
+			rand.Read(seeds)
+
+			eks, dks, err := p.KeyGenBatch(context.Background(), n, bytes.NewReader(seeds))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(eks) != n || len(dks) != n {
+				t.Fatalf("got %d and %d keys, expected %d", len(eks), len(dks), n)
+			}
+			r := bytes.NewReader(seeds)
+			for i := range n {
+				ek, dk, err := p.KeyGenRand(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(eks[i], ek) || !bytes.Equal(dks[i], dk) {
+					t.Fatalf("key pair %d does not match sequential generation", i)
+				}
+			}
+
+			// Appending to one key must not overwrite the next one.
+			ek1 := bytes.Clone(eks[1])
+			_ = append(eks[0], 0xff)
+			if !bytes.Equal(eks[1], ek1) {
+				t.Error("append overwrote the next key")
+			}
+		})
+	}
+}
+
+func TestKeyGenBatchErrors(t *testing.T) {
+	p := mlkem.MLKEM_768
+
+	eks, dks, err := p.KeyGenBatch(context.Background(), 0, rand.Reader)
+	if err != nil || len(eks) != 0 || len(dks) != 0 {
+		t.Errorf("empty batch: %d, %d, %v", len(eks), len(dks), err)
+	}
+
+	_, _, err = p.KeyGenBatch(context.Background(), -1, rand.Reader)
+	if e := (*mlkem.Error)(nil); !errors.As(err, &e) || e.Input != "n" || !errors.Is(err, mlkem.ErrInvalidLength) {
+		t.Errorf("expected length error, got: %v", err)
+	}
+
+	_, _, err = p.KeyGenBatch(context.Background(), 2, bytes.NewReader(make([]byte, mlkem.SeedSize)))
+	if !errors.Is(err, mlkem.ErrEntropy) {
+		t.Errorf("expected entropy error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := p.KeyGenBatch(ctx, 100, rand.Reader); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestDecapsulateBatch(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			ek, dk := p.KeyGen()
+			var cs []mlkem.Ciphertext
+			for range 20 {
+				_, c, err := p.Encaps(ek)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cs = append(cs, c)
+			}
+			cs[3][0] ^= 1                // implicit rejection
+			cs[5] = cs[5][:len(cs[5])-1] // invalid length
+
+			Ks, errs, err := p.DecapsulateBatch(context.Background(), dk, cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, c := range cs {
+				K, err := p.Decaps(dk, c)
+				if !bytes.Equal(Ks[i], K) || (errs[i] == nil) != (err == nil) {
+					t.Errorf("%d: got %x, %v, expected %x, %v", i, Ks[i], errs[i], K, err)
+				}
+			}
+			if !errors.Is(errs[5], mlkem.ErrInvalidLength) {
+				t.Errorf("expected length error, got: %v", errs[5])
+			}
+
+			bad := bytes.Clone(dk)
+			bad[len(bad)-33] ^= 1
+			if _, _, err := p.DecapsulateBatch(context.Background(), bad, cs); !errors.Is(err, mlkem.ErrDecapsulationKeyHash) {
+				t.Errorf("expected hash error, got: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Ks, errs, err = p.DecapsulateBatch(ctx, dk, cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range cs {
+				if Ks[i] != nil || !errors.Is(errs[i], context.Canceled) {
+					t.Errorf("%d: expected context.Canceled, got %x, %v", i, Ks[i], errs[i])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkKeyGenBatch(b *testing.B) {
+	const n = 256
+	p := mlkem.MLKEM_768
+	for b.Loop() {
+		if _, _, err := p.KeyGenBatch(context.Background(), n, rand.Reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/key")
+}
+
+func BenchmarkDecapsulateBatch(b *testing.B) {
+	const n = 256
+	p := mlkem.MLKEM_768
+	ek, dk := p.KeyGen()
+	cs := make([]mlkem.Ciphertext, n)
+	for i := range cs {
+		_, cs[i], _ = p.Encaps(ek)
+	}
+	d, err := p.NewDecapsulator(dk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		d.DecapsulateBatch(context.Background(), cs)
+	}
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/ciphertext")
+}
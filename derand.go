@@ -0,0 +1,46 @@
+package mlkem
+
+// The functions in this file expose the deterministic ("derandomized") algorithms
+// of FIPS 203 §6 that take their randomness as explicit inputs.
+// They are intended for reproducing test vectors and for golden-file tests only:
+// the randomness d, z and m must never be reused and must come from
+// an approved random bit generator in production, see [ParameterSet.KeyGen] and [ParameterSet.Encaps].
+
+// KeyGenDerand implements ML-KEM.KeyGen_internal (FIPS 203 Algorithm 16).
+// It produces an encapsulation key and a decapsulation key from 32-byte randomness d and z.
+func (p ParameterSet) KeyGenDerand(d, z []byte) (EncapsulationKey, DecapsulationKey, error) {
+	if err := p.checkValid("KeyGenDerand"); err != nil {
+		return nil, nil, err
+	}
+	if len(d) != 32 {
+		return nil, nil, p.newError("KeyGenDerand", "d", ErrInvalidLength)
+	}
+	if len(z) != 32 {
+		return nil, nil, p.newError("KeyGenDerand", "z", ErrInvalidLength)
+	}
+	ek, dk := p.keyGen(nil, nil, d, z)
+	return ek, dk, nil
+}
+
+// EncapsDerand implements ML-KEM.Encaps_internal (FIPS 203 Algorithm 17).
+// It validates the encapsulation key and encapsulates using 32-byte randomness m.
+func (p ParameterSet) EncapsDerand(ek EncapsulationKey, m []byte) (SharedKey, Ciphertext, error) {
+	if err := p.checkEncapsulationKey("EncapsDerand", ek); err != nil {
+		return nil, nil, err
+	}
+	if len(m) != 32 {
+		return nil, nil, p.newError("EncapsDerand", "m", ErrInvalidLength)
+	}
+	K, c := p.encaps(nil, nil, ek, m)
+	return K, c, nil
+}
+
+// EncapsulateDerand implements ML-KEM.Encaps_internal (FIPS 203 Algorithm 17)
+// using 32-byte randomness m.
+func (e *Encapsulator) EncapsulateDerand(m []byte) (SharedKey, Ciphertext, error) {
+	if len(m) != 32 {
+		return nil, nil, e.p.newError("EncapsulateDerand", "m", ErrInvalidLength)
+	}
+	K, c := e.encapsulate(nil, nil, m)
+	return K, c, nil
+}
@@ -0,0 +1,102 @@
+package mlkem_test
+
+import (
+	"bytes"
+	stdmlkem "crypto/mlkem"
+	"crypto/rand"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestDerand(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			seed := make([]byte, 64)
+			rand.Read(seed)
+			d, z := seed[:32], seed[32:]
+
+			ek, dk, err := p.KeyGenDerand(d, z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ek2, dk2, err := p.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ek, ek2) || !bytes.Equal(dk, dk2) {
+				t.Error("KeyGenDerand does not match KeySeed")
+			}
+
+			m := make([]byte, 32)
+			rand.Read(m)
+			K1, c1, err := p.EncapsDerand(ek, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e, err := p.NewEncapsulator(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			K2, c2, err := e.EncapsulateDerand(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(K1, K2) || !bytes.Equal(c1, c2) {
+				t.Error("EncapsulateDerand does not match EncapsDerand")
+			}
+
+			K3, err := p.Decaps(dk, c1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(K1, K3) {
+				t.Errorf("%x != %x", K1, K3)
+			}
+
+			if _, _, err := p.KeyGenDerand(d[1:], z); err == nil {
+				t.Error("KeyGenDerand: expected error for short d")
+			}
+			if _, _, err := p.KeyGenDerand(d, z[1:]); err == nil {
+				t.Error("KeyGenDerand: expected error for short z")
+			}
+			if _, _, err := p.EncapsDerand(ek, m[1:]); err == nil {
+				t.Error("EncapsDerand: expected error for short m")
+			}
+			if _, _, err := e.EncapsulateDerand(m[1:]); err == nil {
+				t.Error("EncapsulateDerand: expected error for short m")
+			}
+		})
+	}
+}
+
+func TestDerandCompatibility(t *testing.T) {
+	dk, err := stdmlkem.GenerateKey768()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := dk.Bytes()
+
+	ek, _, err := mlkem.MLKEM_768.KeyGenDerand(seed[:32], seed[32:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := make([]byte, 32)
+	rand.Read(m)
+	K1, c, err := mlkem.MLKEM_768.EncapsDerand(ek, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	K2, err := dk.Decapsulate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(K1, K2) {
+		t.Error("K1 != K2")
+	}
+}
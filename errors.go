@@ -0,0 +1,74 @@
+package mlkem
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidLength is returned when a key, ciphertext, seed or randomness has an invalid length,
+	// or when a negative number of keys is requested from [ParameterSet.KeyGenBatch].
+	ErrInvalidLength = errors.New("invalid length")
+
+	// ErrEncapsulationKeyModulus is returned when an encapsulation key
+	// contains a coefficient that is not reduced modulo q.
+	ErrEncapsulationKeyModulus = errors.New("encapsulation key modulus check failed")
+
+	// ErrDecapsulationKeyModulus is returned when the secret vector of a decapsulation key
+	// contains a coefficient that is not reduced modulo q.
+	ErrDecapsulationKeyModulus = errors.New("decapsulation key modulus check failed")
+
+	// ErrDecapsulationKeyHash is returned when the hash stored in a decapsulation key
+	// does not match the hash of the embedded encapsulation key.
+	ErrDecapsulationKeyHash = errors.New("decapsulation key hash check failed")
+
+	// ErrEntropy is returned when the source of randomness fails or does not provide enough bytes.
+	// The error returned by the source is wrapped as well.
+	ErrEntropy = errors.New("entropy source failure")
+
+	// ErrParameterSetMismatch is returned when a key of one parameter set is used with another.
+	ErrParameterSetMismatch = errors.New("parameter set mismatch")
+
+	// ErrNoSeed is returned when a seed is required but the key was parsed from the expanded form.
+	ErrNoSeed = errors.New("key has no seed")
+
+	// ErrKeyDestroyed is returned when a decapsulation key is used after Destroy.
+	ErrKeyDestroyed = errors.New("key has been destroyed")
+
+	// ErrLockedMemory is returned when locked memory can not be allocated,
+	// e.g. because RLIMIT_MEMLOCK is exceeded. The error returned by the system is wrapped as well.
+	ErrLockedMemory = errors.New("locked memory allocation failed")
+
+	// ErrInvalidParameterSet is returned when the ParameterSet is not one of
+	// [MLKEM_512], [MLKEM_768] or [MLKEM_1024], e.g. its zero value.
+	ErrInvalidParameterSet = errors.New("invalid parameter set")
+)
+
+// Error records the parameter set, the operation and the input that caused a failure.
+// Use [errors.Is] with one of the sentinel errors to check the reason.
+type Error struct {
+	ParameterSet ParameterSet
+	Op           string // operation that failed, e.g. "Encaps"
+	Input        string // input that failed the check, e.g. "ek", "dk", "c", "seed" or "rand"
+	Err          error  // reason
+}
+
+func (e *Error) Error() string {
+	s := "mlkem: " + e.ParameterSet.String() + " " + e.Op
+	if e.Input != "" {
+		s += ": " + e.Input
+	}
+	return s + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (p ParameterSet) newError(op, input string, err error) error {
+	return &Error{ParameterSet: p, Op: op, Input: input, Err: err}
+}
+
+func (p ParameterSet) entropyError(op string, err error) error {
+	return p.newError(op, "rand", fmt.Errorf("%w: %w", ErrEntropy, err))
+}
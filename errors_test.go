@@ -0,0 +1,188 @@
+package mlkem_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestError(t *testing.T) {
+	p := mlkem.MLKEM_768
+	ek, dk := p.KeyGen()
+	_, c, err := p.Encaps(ek)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errSource := errors.New("source failure")
+	failingReader := readerFunc(func([]byte) (int, error) { return 0, errSource })
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		op     string
+		input  string
+		reason error
+	}{
+		{
+			name:   "KeySeed",
+			err:    third(p.KeySeed(make([]byte, 63))),
+			op:     "KeySeed",
+			input:  "seed",
+			reason: mlkem.ErrInvalidLength,
+		},
+		{
+			name:   "Encaps",
+			err:    third(p.Encaps(ek[1:])),
+			op:     "Encaps",
+			input:  "ek",
+			reason: mlkem.ErrInvalidLength,
+		},
+		{
+			name:   "Decaps ciphertext",
+			err:    second(p.Decaps(dk, c[1:])),
+			op:     "Decaps",
+			input:  "c",
+			reason: mlkem.ErrInvalidLength,
+		},
+		{
+			name:   "Decaps key",
+			err:    second(p.Decaps(dk[1:], c)),
+			op:     "Decaps",
+			input:  "dk",
+			reason: mlkem.ErrInvalidLength,
+		},
+		{
+			name:   "KeyGenRand",
+			err:    third(p.KeyGenRand(failingReader)),
+			op:     "KeyGenRand",
+			input:  "rand",
+			reason: mlkem.ErrEntropy,
+		},
+		{
+			name:   "KeyGenDerand",
+			err:    third(p.KeyGenDerand(make([]byte, 32), nil)),
+			op:     "KeyGenDerand",
+			input:  "z",
+			reason: mlkem.ErrInvalidLength,
+		},
+		{
+			name:   "NewSeedDecapsulator",
+			err:    second(p.NewSeedDecapsulator(nil)),
+			op:     "NewSeedDecapsulator",
+			input:  "seed",
+			reason: mlkem.ErrInvalidLength,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if !errors.Is(tc.err, tc.reason) {
+				t.Errorf("expected %v, got: %v", tc.reason, tc.err)
+			}
+			var e *mlkem.Error
+			if !errors.As(tc.err, &e) {
+				t.Fatalf("expected *mlkem.Error, got: %T", tc.err)
+			}
+			if e.ParameterSet != p {
+				t.Errorf("expected %v, got: %v", p, e.ParameterSet)
+			}
+			if e.Op != tc.op {
+				t.Errorf("expected op %q, got: %q", tc.op, e.Op)
+			}
+			if e.Input != tc.input {
+				t.Errorf("expected input %q, got: %q", tc.input, e.Input)
+			}
+			t.Log(tc.err)
+		})
+	}
+
+	t.Run("entropy source error", func(t *testing.T) {
+		_, _, err := p.KeyGenRand(failingReader)
+		if !errors.Is(err, errSource) {
+			t.Errorf("expected source error to be wrapped, got: %v", err)
+		}
+	})
+
+	t.Run("no seed", func(t *testing.T) {
+		d, err := mlkem.MLKEM_512.NewDecapsulator(func() mlkem.DecapsulationKey {
+			_, dk := mlkem.MLKEM_512.KeyGen()
+			return dk
+		}())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = mlkem.NewDecapsulationKey512FromDecapsulator(d)
+		if !errors.Is(err, mlkem.ErrNoSeed) {
+			t.Errorf("expected %v, got: %v", mlkem.ErrNoSeed, err)
+		}
+	})
+}
+
+func TestInvalidParameterSet(t *testing.T) {
+	ek, dk := mlkem.MLKEM_768.KeyGen()
+	_, c, err := mlkem.MLKEM_768.Encaps(ek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := make([]byte, mlkem.SeedSize)
+
+	for _, p := range []mlkem.ParameterSet{0, mlkem.MLKEM_1024 + 1} {
+		for _, tc := range []struct {
+			op  string
+			err error
+		}{
+			{"KeyGenRand", third(p.KeyGenRand(rand.Reader))},
+			{"KeySeed", third(p.KeySeed(seed))},
+			{"KeyGenDerand", third(p.KeyGenDerand(seed[:32], seed[32:]))},
+			{"KeyGenWithSeedRand", fourth(p.KeyGenWithSeedRand(rand.Reader))},
+			{"ExpandSeed", second(p.ExpandSeed(seed))},
+			{"Encaps", third(p.Encaps(ek))},
+			{"EncapsRand", third(p.EncapsRand(rand.Reader, ek))},
+			{"EncapsDerand", third(p.EncapsDerand(ek, seed[:32]))},
+			{"Decaps", second(p.Decaps(dk, c))},
+			{"ValidateEncapsulationKey", p.ValidateEncapsulationKey(ek)},
+			{"ValidateDecapsulationKey", p.ValidateDecapsulationKey(dk)},
+			{"NewEncapsulator", second(p.NewEncapsulator(ek))},
+			{"NewDecapsulator", second(p.NewDecapsulator(dk))},
+			{"NewDecapsulatorFromSeed", second(p.NewDecapsulatorFromSeed(seed))},
+			{"GenerateDecapsulatorRand", second(p.GenerateDecapsulatorRand(rand.Reader))},
+			{"NewSeedDecapsulator", second(p.NewSeedDecapsulator(seed))},
+			{"GenerateSeedDecapsulatorRand", second(p.GenerateSeedDecapsulatorRand(rand.Reader))},
+			{"NewLockedDecapsulator", second(p.NewLockedDecapsulator(dk))},
+			{"NewLockedDecapsulatorFromSeed", second(p.NewLockedDecapsulatorFromSeed(seed))},
+			{"NewLockedSeedDecapsulator", second(p.NewLockedSeedDecapsulator(seed))},
+			{"KeyGenBatch", third(p.KeyGenBatch(context.Background(), 1, rand.Reader))},
+			{"DecapsulateBatch", third(p.DecapsulateBatch(context.Background(), dk, []mlkem.Ciphertext{c}))},
+		} {
+			t.Run(p.String()+"/"+tc.op, func(t *testing.T) {
+				var e *mlkem.Error
+				if !errors.As(tc.err, &e) || !errors.Is(tc.err, mlkem.ErrInvalidParameterSet) {
+					t.Fatalf("expected %v, got: %v", mlkem.ErrInvalidParameterSet, tc.err)
+				}
+				if e.ParameterSet != p || e.Op != tc.op {
+					t.Errorf("unexpected error: %v", tc.err)
+				}
+			})
+		}
+	}
+}
+
+type readerFunc func([]byte) (int, error)
+
+func (f readerFunc) Read(b []byte) (int, error) {
+	return f(b)
+}
+
+func second[A, B any](_ A, b B) B {
+	return b
+}
+
+func third[A, B, C any](_ A, _ B, c C) C {
+	return c
+}
+
+func fourth[A, B, C, D any](_ A, _ B, _ C, d D) D {
+	return d
+}
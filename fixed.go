@@ -0,0 +1,328 @@
+package mlkem
+
+import (
+	"bytes"
+	"crypto/rand"
+)
+
+// The types in this file are fixed-size counterparts of [EncapsulationKey], [DecapsulationKey],
+// [Ciphertext] and [SharedKey]. Each parameter set has its own array types of exactly its sizes,
+// so that using a key or a ciphertext of one parameter set with another,
+// or a decapsulation key in place of an encapsulation key, does not compile.
+// They do not need heap allocations.
+
+// FixedSharedKey is a shared key, which has the same size for all parameter sets.
+type FixedSharedKey [SharedKeySize]byte
+
+// Bytes returns a copy of the shared key as a [SharedKey].
+func (K *FixedSharedKey) Bytes() SharedKey {
+	return bytes.Clone(K[:])
+}
+
+func generateFixed(p ParameterSet, ek, dk []byte) {
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	rand.Read(dz[:]) // crypto/rand.Read never fails
+	p.keyGen(ek[:0], dk[:0], dz[:32], dz[32:])
+}
+
+// copyFixed copies src into dst if it has the length of dst.
+func copyFixed(p ParameterSet, op, input string, dst, src []byte) error {
+	if len(src) != len(dst) {
+		return p.newError(op, input, ErrInvalidLength)
+	}
+	copy(dst, src)
+	return nil
+}
+
+func fixedEncaps(p ParameterSet, K *FixedSharedKey, c, ek []byte) error {
+	if err := p.checkEncapsulationKey("Encaps", ek); err != nil {
+		return err
+	}
+	var m [32]byte
+	defer clear(m[:])
+	rand.Read(m[:]) // crypto/rand.Read never fails
+	p.encaps(K[:0], c[:0], ek, m[:])
+	return nil
+}
+
+func fixedDecaps(p ParameterSet, K *FixedSharedKey, dk, c []byte) error {
+	_, err := p.decapsTo("Decaps", K[:0], dk, c)
+	return err
+}
+
+type (
+	// FixedEncapsulationKey512 is an ML-KEM-512 encapsulation key.
+	FixedEncapsulationKey512 [384*2 + 32]byte
+
+	// FixedDecapsulationKey512 is an expanded ML-KEM-512 decapsulation key.
+	// Decapsulation key shall remain private.
+	FixedDecapsulationKey512 [768*2 + 96]byte
+
+	// FixedCiphertext512 is an ML-KEM-512 ciphertext.
+	FixedCiphertext512 [32 * (10*2 + 4)]byte
+)
+
+// GenerateFixed512 generates randomness internally and produces an ML-KEM-512 key pair.
+func GenerateFixed512() (ek FixedEncapsulationKey512, dk FixedDecapsulationKey512) {
+	generateFixed(MLKEM_512, ek[:], dk[:])
+	return ek, dk
+}
+
+// NewFixedEncapsulationKey512 copies ek into a [FixedEncapsulationKey512].
+// It returns an error if ek does not have the ML-KEM-512 length.
+// The key is validated by [FixedEncapsulationKey512.Encaps].
+func NewFixedEncapsulationKey512(ek EncapsulationKey) (*FixedEncapsulationKey512, error) {
+	k := new(FixedEncapsulationKey512)
+	if err := copyFixed(MLKEM_512, "NewFixedEncapsulationKey512", "ek", k[:], ek); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewFixedDecapsulationKey512 copies dk into a [FixedDecapsulationKey512].
+// It returns an error if dk does not have the ML-KEM-512 length.
+// The key is validated by [FixedDecapsulationKey512.Decaps].
+func NewFixedDecapsulationKey512(dk DecapsulationKey) (*FixedDecapsulationKey512, error) {
+	k := new(FixedDecapsulationKey512)
+	if err := copyFixed(MLKEM_512, "NewFixedDecapsulationKey512", "dk", k[:], dk); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewFixedCiphertext512 copies c into a [FixedCiphertext512].
+// It returns an error if c does not have the ML-KEM-512 length.
+func NewFixedCiphertext512(c Ciphertext) (*FixedCiphertext512, error) {
+	fc := new(FixedCiphertext512)
+	if err := copyFixed(MLKEM_512, "NewFixedCiphertext512", "c", fc[:], c); err != nil {
+		return nil, err
+	}
+	return fc, nil
+}
+
+// ParameterSet returns [MLKEM_512].
+func (*FixedEncapsulationKey512) ParameterSet() ParameterSet { return MLKEM_512 }
+
+// ParameterSet returns [MLKEM_512].
+func (*FixedDecapsulationKey512) ParameterSet() ParameterSet { return MLKEM_512 }
+
+// ParameterSet returns [MLKEM_512].
+func (*FixedCiphertext512) ParameterSet() ParameterSet { return MLKEM_512 }
+
+// Bytes returns a copy of the key as an [EncapsulationKey].
+func (ek *FixedEncapsulationKey512) Bytes() EncapsulationKey {
+	return bytes.Clone(ek[:])
+}
+
+// Bytes returns a copy of the key as a [DecapsulationKey].
+func (dk *FixedDecapsulationKey512) Bytes() DecapsulationKey {
+	return bytes.Clone(dk[:])
+}
+
+// Bytes returns a copy of the ciphertext as a [Ciphertext].
+func (c *FixedCiphertext512) Bytes() Ciphertext {
+	return bytes.Clone(c[:])
+}
+
+// Encaps is like [ParameterSet.Encaps] for ML-KEM-512.
+func (ek *FixedEncapsulationKey512) Encaps() (K FixedSharedKey, c FixedCiphertext512, err error) {
+	err = fixedEncaps(MLKEM_512, &K, c[:], ek[:])
+	return K, c, err
+}
+
+// EncapsulationKey returns the encapsulation key embedded in the decapsulation key.
+func (dk *FixedDecapsulationKey512) EncapsulationKey() (ek FixedEncapsulationKey512) {
+	copy(ek[:], dk[384*2:])
+	return ek
+}
+
+// Decaps is like [ParameterSet.Decaps] for ML-KEM-512.
+func (dk *FixedDecapsulationKey512) Decaps(c *FixedCiphertext512) (K FixedSharedKey, err error) {
+	err = fixedDecaps(MLKEM_512, &K, dk[:], c[:])
+	return K, err
+}
+
+type (
+	// FixedEncapsulationKey768 is an ML-KEM-768 encapsulation key.
+	FixedEncapsulationKey768 [384*3 + 32]byte
+
+	// FixedDecapsulationKey768 is an expanded ML-KEM-768 decapsulation key.
+	// Decapsulation key shall remain private.
+	FixedDecapsulationKey768 [768*3 + 96]byte
+
+	// FixedCiphertext768 is an ML-KEM-768 ciphertext.
+	FixedCiphertext768 [32 * (10*3 + 4)]byte
+)
+
+// GenerateFixed768 generates randomness internally and produces an ML-KEM-768 key pair.
+func GenerateFixed768() (ek FixedEncapsulationKey768, dk FixedDecapsulationKey768) {
+	generateFixed(MLKEM_768, ek[:], dk[:])
+	return ek, dk
+}
+
+// NewFixedEncapsulationKey768 copies ek into a [FixedEncapsulationKey768].
+// It returns an error if ek does not have the ML-KEM-768 length.
+// The key is validated by [FixedEncapsulationKey768.Encaps].
+func NewFixedEncapsulationKey768(ek EncapsulationKey) (*FixedEncapsulationKey768, error) {
+	k := new(FixedEncapsulationKey768)
+	if err := copyFixed(MLKEM_768, "NewFixedEncapsulationKey768", "ek", k[:], ek); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewFixedDecapsulationKey768 copies dk into a [FixedDecapsulationKey768].
+// It returns an error if dk does not have the ML-KEM-768 length.
+// The key is validated by [FixedDecapsulationKey768.Decaps].
+func NewFixedDecapsulationKey768(dk DecapsulationKey) (*FixedDecapsulationKey768, error) {
+	k := new(FixedDecapsulationKey768)
+	if err := copyFixed(MLKEM_768, "NewFixedDecapsulationKey768", "dk", k[:], dk); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewFixedCiphertext768 copies c into a [FixedCiphertext768].
+// It returns an error if c does not have the ML-KEM-768 length.
+func NewFixedCiphertext768(c Ciphertext) (*FixedCiphertext768, error) {
+	fc := new(FixedCiphertext768)
+	if err := copyFixed(MLKEM_768, "NewFixedCiphertext768", "c", fc[:], c); err != nil {
+		return nil, err
+	}
+	return fc, nil
+}
+
+// ParameterSet returns [MLKEM_768].
+func (*FixedEncapsulationKey768) ParameterSet() ParameterSet { return MLKEM_768 }
+
+// ParameterSet returns [MLKEM_768].
+func (*FixedDecapsulationKey768) ParameterSet() ParameterSet { return MLKEM_768 }
+
+// ParameterSet returns [MLKEM_768].
+func (*FixedCiphertext768) ParameterSet() ParameterSet { return MLKEM_768 }
+
+// Bytes returns a copy of the key as an [EncapsulationKey].
+func (ek *FixedEncapsulationKey768) Bytes() EncapsulationKey {
+	return bytes.Clone(ek[:])
+}
+
+// Bytes returns a copy of the key as a [DecapsulationKey].
+func (dk *FixedDecapsulationKey768) Bytes() DecapsulationKey {
+	return bytes.Clone(dk[:])
+}
+
+// Bytes returns a copy of the ciphertext as a [Ciphertext].
+func (c *FixedCiphertext768) Bytes() Ciphertext {
+	return bytes.Clone(c[:])
+}
+
+// Encaps is like [ParameterSet.Encaps] for ML-KEM-768.
+func (ek *FixedEncapsulationKey768) Encaps() (K FixedSharedKey, c FixedCiphertext768, err error) {
+	err = fixedEncaps(MLKEM_768, &K, c[:], ek[:])
+	return K, c, err
+}
+
+// EncapsulationKey returns the encapsulation key embedded in the decapsulation key.
+func (dk *FixedDecapsulationKey768) EncapsulationKey() (ek FixedEncapsulationKey768) {
+	copy(ek[:], dk[384*3:])
+	return ek
+}
+
+// Decaps is like [ParameterSet.Decaps] for ML-KEM-768.
+func (dk *FixedDecapsulationKey768) Decaps(c *FixedCiphertext768) (K FixedSharedKey, err error) {
+	err = fixedDecaps(MLKEM_768, &K, dk[:], c[:])
+	return K, err
+}
+
+type (
+	// FixedEncapsulationKey1024 is an ML-KEM-1024 encapsulation key.
+	FixedEncapsulationKey1024 [384*4 + 32]byte
+
+	// FixedDecapsulationKey1024 is an expanded ML-KEM-1024 decapsulation key.
+	// Decapsulation key shall remain private.
+	FixedDecapsulationKey1024 [768*4 + 96]byte
+
+	// FixedCiphertext1024 is an ML-KEM-1024 ciphertext.
+	FixedCiphertext1024 [32 * (11*4 + 5)]byte
+)
+
+// GenerateFixed1024 generates randomness internally and produces an ML-KEM-1024 key pair.
+func GenerateFixed1024() (ek FixedEncapsulationKey1024, dk FixedDecapsulationKey1024) {
+	generateFixed(MLKEM_1024, ek[:], dk[:])
+	return ek, dk
+}
+
+// NewFixedEncapsulationKey1024 copies ek into a [FixedEncapsulationKey1024].
+// It returns an error if ek does not have the ML-KEM-1024 length.
+// The key is validated by [FixedEncapsulationKey1024.Encaps].
+func NewFixedEncapsulationKey1024(ek EncapsulationKey) (*FixedEncapsulationKey1024, error) {
+	k := new(FixedEncapsulationKey1024)
+	if err := copyFixed(MLKEM_1024, "NewFixedEncapsulationKey1024", "ek", k[:], ek); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewFixedDecapsulationKey1024 copies dk into a [FixedDecapsulationKey1024].
+// It returns an error if dk does not have the ML-KEM-1024 length.
+// The key is validated by [FixedDecapsulationKey1024.Decaps].
+func NewFixedDecapsulationKey1024(dk DecapsulationKey) (*FixedDecapsulationKey1024, error) {
+	k := new(FixedDecapsulationKey1024)
+	if err := copyFixed(MLKEM_1024, "NewFixedDecapsulationKey1024", "dk", k[:], dk); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// NewFixedCiphertext1024 copies c into a [FixedCiphertext1024].
+// It returns an error if c does not have the ML-KEM-1024 length.
+func NewFixedCiphertext1024(c Ciphertext) (*FixedCiphertext1024, error) {
+	fc := new(FixedCiphertext1024)
+	if err := copyFixed(MLKEM_1024, "NewFixedCiphertext1024", "c", fc[:], c); err != nil {
+		return nil, err
+	}
+	return fc, nil
+}
+
+// ParameterSet returns [MLKEM_1024].
+func (*FixedEncapsulationKey1024) ParameterSet() ParameterSet { return MLKEM_1024 }
+
+// ParameterSet returns [MLKEM_1024].
+func (*FixedDecapsulationKey1024) ParameterSet() ParameterSet { return MLKEM_1024 }
+
+// ParameterSet returns [MLKEM_1024].
+func (*FixedCiphertext1024) ParameterSet() ParameterSet { return MLKEM_1024 }
+
+// Bytes returns a copy of the key as an [EncapsulationKey].
+func (ek *FixedEncapsulationKey1024) Bytes() EncapsulationKey {
+	return bytes.Clone(ek[:])
+}
+
+// Bytes returns a copy of the key as a [DecapsulationKey].
+func (dk *FixedDecapsulationKey1024) Bytes() DecapsulationKey {
+	return bytes.Clone(dk[:])
+}
+
+// Bytes returns a copy of the ciphertext as a [Ciphertext].
+func (c *FixedCiphertext1024) Bytes() Ciphertext {
+	return bytes.Clone(c[:])
+}
+
+// Encaps is like [ParameterSet.Encaps] for ML-KEM-1024.
+func (ek *FixedEncapsulationKey1024) Encaps() (K FixedSharedKey, c FixedCiphertext1024, err error) {
+	err = fixedEncaps(MLKEM_1024, &K, c[:], ek[:])
+	return K, c, err
+}
+
+// EncapsulationKey returns the encapsulation key embedded in the decapsulation key.
+func (dk *FixedDecapsulationKey1024) EncapsulationKey() (ek FixedEncapsulationKey1024) {
+	copy(ek[:], dk[384*4:])
+	return ek
+}
+
+// Decaps is like [ParameterSet.Decaps] for ML-KEM-1024.
+func (dk *FixedDecapsulationKey1024) Decaps(c *FixedCiphertext1024) (K FixedSharedKey, err error) {
+	err = fixedDecaps(MLKEM_1024, &K, dk[:], c[:])
+	return K, err
+}
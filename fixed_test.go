@@ -0,0 +1,159 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"unsafe"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestFixed(t *testing.T) {
+	t.Run("ML-KEM-512", func(t *testing.T) {
+		testFixed(t, mlkem.GenerateFixed512,
+			mlkem.NewFixedEncapsulationKey512, mlkem.NewFixedDecapsulationKey512, mlkem.NewFixedCiphertext512)
+	})
+	t.Run("ML-KEM-768", func(t *testing.T) {
+		testFixed(t, mlkem.GenerateFixed768,
+			mlkem.NewFixedEncapsulationKey768, mlkem.NewFixedDecapsulationKey768, mlkem.NewFixedCiphertext768)
+	})
+	t.Run("ML-KEM-1024", func(t *testing.T) {
+		testFixed(t, mlkem.GenerateFixed1024,
+			mlkem.NewFixedEncapsulationKey1024, mlkem.NewFixedDecapsulationKey1024, mlkem.NewFixedCiphertext1024)
+	})
+}
+
+// The method sets shared by the fixed-size types of all parameter sets.
+type (
+	fixedEncapsulationKey[EK, C any] interface {
+		*EK
+		ParameterSet() mlkem.ParameterSet
+		Bytes() mlkem.EncapsulationKey
+		Encaps() (mlkem.FixedSharedKey, C, error)
+	}
+	fixedDecapsulationKey[DK, EK, C any] interface {
+		*DK
+		ParameterSet() mlkem.ParameterSet
+		Bytes() mlkem.DecapsulationKey
+		EncapsulationKey() EK
+		Decaps(*C) (mlkem.FixedSharedKey, error)
+	}
+	fixedCiphertext[C any] interface {
+		*C
+		ParameterSet() mlkem.ParameterSet
+		Bytes() mlkem.Ciphertext
+	}
+)
+
+func testFixed[EK, DK, C comparable,
+	PEK fixedEncapsulationKey[EK, C], PDK fixedDecapsulationKey[DK, EK, C], PC fixedCiphertext[C]](
+	t *testing.T,
+	generate func() (EK, DK),
+	newEK func(mlkem.EncapsulationKey) (*EK, error),
+	newDK func(mlkem.DecapsulationKey) (*DK, error),
+	newC func(mlkem.Ciphertext) (*C, error),
+) {
+	ek, dk := generate()
+	p := PEK(&ek).ParameterSet()
+	if p.String() != t.Name()[len("TestFixed/"):] {
+		t.Fatalf("unexpected parameter set %v", p)
+	}
+	if PDK(&dk).ParameterSet() != p {
+		t.Fatal("parameter set mismatch")
+	}
+
+	// The arrays have exactly the sizes of the parameter set.
+	if int(unsafe.Sizeof(ek)) != p.EncapsulationKeySize() ||
+		int(unsafe.Sizeof(dk)) != p.DecapsulationKeySize() ||
+		int(unsafe.Sizeof(*new(C))) != p.CiphertextSize() {
+		t.Error("unexpected array sizes")
+	}
+
+	K1, c, err := PEK(&ek).Encaps()
+	if err != nil {
+		t.Fatal(err)
+	}
+	K2, err := PDK(&dk).Decaps(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if K1 != K2 {
+		t.Error("shared key mismatch")
+	}
+	if e := PDK(&dk).EncapsulationKey(); e != ek {
+		t.Error("embedded encapsulation key mismatch")
+	}
+
+	// Conversions to and from the slice types.
+	K3, err := p.Decaps(PDK(&dk).Bytes(), PC(&c).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(K3, K1.Bytes()) {
+		t.Error("shared key mismatch")
+	}
+	K4, c2, err := p.Encaps(PEK(&ek).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := newC(c2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fdk, err := newDK(PDK(&dk).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if K5, err := PDK(fdk).Decaps(fc); err != nil || !bytes.Equal(K5[:], K4) {
+		t.Errorf("shared key mismatch: %v", err)
+	}
+	fek, err := newEK(PEK(&ek).Bytes())
+	if err != nil || *fek != ek {
+		t.Errorf("encapsulation key does not round-trip: %v", err)
+	}
+
+	if _, err := newC(c2[1:]); !errors.Is(err, mlkem.ErrInvalidLength) {
+		t.Errorf("expected length error, got: %v", err)
+	}
+	if _, err := newEK(mlkem.EncapsulationKey(PDK(&dk).Bytes())); !errors.Is(err, mlkem.ErrInvalidLength) {
+		t.Errorf("expected length error, got: %v", err)
+	}
+
+	var zero DK
+	if _, err := PDK(&zero).Decaps(&c); !errors.Is(err, mlkem.ErrDecapsulationKeyHash) {
+		t.Errorf("expected hash error, got: %v", err)
+	}
+}
+
+func TestFixedAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector allocates")
+	}
+	for _, tc := range []struct {
+		name string
+		f    func()
+	}{
+		{"ML-KEM-512", func() {
+			ek, dk := mlkem.GenerateFixed512()
+			_, c, _ := ek.Encaps()
+			_, _ = dk.Decaps(&c)
+		}},
+		{"ML-KEM-768", func() {
+			ek, dk := mlkem.GenerateFixed768()
+			_, c, _ := ek.Encaps()
+			_, _ = dk.Decaps(&c)
+		}},
+		{"ML-KEM-1024", func() {
+			ek, dk := mlkem.GenerateFixed1024()
+			_, c, _ := ek.Encaps()
+			_, _ = dk.Decaps(&c)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if avg := testing.AllocsPerRun(10, tc.f); avg > 0 {
+				t.Errorf("Non-zero allocs: %f", avg)
+			}
+		})
+	}
+}
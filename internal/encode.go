@@ -0,0 +1,291 @@
+package internal
+
+// compressed holds d-bit values such as the output of Compress_d or the input of ByteEncode_d,
+// one per coefficient.
+type compressed [256]uint16
+
+// byteEncode writes the 32d-byte encoding of the d-bit values f to b.
+// len(f) must be 256.
+//
+// The widths used by ML-KEM pack groups of 8 values into d bytes directly,
+// other widths fall back to byteEncodeGeneric.
+func byteEncode(b []byte, f []uint16, d int) {
+	switch d {
+	case 1:
+		for i := 0; i < len(f); i += 8 {
+			encode1((*[1]byte)(b[i/8*1:]), (*[8]uint16)(f[i:]))
+		}
+	case 4:
+		for i := 0; i < len(f); i += 8 {
+			encode4((*[4]byte)(b[i/8*4:]), (*[8]uint16)(f[i:]))
+		}
+	case 5:
+		for i := 0; i < len(f); i += 8 {
+			encode5((*[5]byte)(b[i/8*5:]), (*[8]uint16)(f[i:]))
+		}
+	case 10:
+		for i := 0; i < len(f); i += 8 {
+			encode10((*[10]byte)(b[i/8*10:]), (*[8]uint16)(f[i:]))
+		}
+	case 11:
+		for i := 0; i < len(f); i += 8 {
+			encode11((*[11]byte)(b[i/8*11:]), (*[8]uint16)(f[i:]))
+		}
+	case 12:
+		for i := 0; i < len(f); i += 8 {
+			encode12((*[12]byte)(b[i/8*12:]), (*[8]uint16)(f[i:]))
+		}
+	default:
+		byteEncodeGeneric(b, f, d)
+	}
+}
+
+// byteDecode sets the 256 values f to the d-bit values decoded from 32d bytes b.
+func byteDecode(f []uint16, b []byte, d int) {
+	switch d {
+	case 1:
+		for i := 0; i < len(f); i += 8 {
+			decode1((*[8]uint16)(f[i:]), (*[1]byte)(b[i/8*1:]))
+		}
+	case 4:
+		for i := 0; i < len(f); i += 8 {
+			decode4((*[8]uint16)(f[i:]), (*[4]byte)(b[i/8*4:]))
+		}
+	case 5:
+		for i := 0; i < len(f); i += 8 {
+			decode5((*[8]uint16)(f[i:]), (*[5]byte)(b[i/8*5:]))
+		}
+	case 10:
+		for i := 0; i < len(f); i += 8 {
+			decode10((*[8]uint16)(f[i:]), (*[10]byte)(b[i/8*10:]))
+		}
+	case 11:
+		for i := 0; i < len(f); i += 8 {
+			decode11((*[8]uint16)(f[i:]), (*[11]byte)(b[i/8*11:]))
+		}
+	case 12:
+		for i := 0; i < len(f); i += 8 {
+			decode12((*[8]uint16)(f[i:]), (*[12]byte)(b[i/8*12:]))
+		}
+	default:
+		byteDecodeGeneric(f, b, d)
+	}
+}
+
+// encode8 writes the d-byte encoding of 8 d-bit values to b.
+func encode8(b []byte, c *[8]uint16, d int) {
+	switch d {
+	case 1:
+		encode1((*[1]byte)(b), c)
+	case 4:
+		encode4((*[4]byte)(b), c)
+	case 5:
+		encode5((*[5]byte)(b), c)
+	case 10:
+		encode10((*[10]byte)(b), c)
+	case 11:
+		encode11((*[11]byte)(b), c)
+	case 12:
+		encode12((*[12]byte)(b), c)
+	default:
+		byteEncodeGeneric(b[:d], c[:], d)
+	}
+}
+
+// encodeN and decodeN convert between 8 N-bit values and their N-byte encoding.
+
+func encode1(b *[1]byte, c *[8]uint16) {
+	b[0] = byte(c[0] | c[1]<<1 | c[2]<<2 | c[3]<<3 | c[4]<<4 | c[5]<<5 | c[6]<<6 | c[7]<<7)
+}
+
+func encode4(b *[4]byte, c *[8]uint16) {
+	b[0] = byte(c[0] | c[1]<<4)
+	b[1] = byte(c[2] | c[3]<<4)
+	b[2] = byte(c[4] | c[5]<<4)
+	b[3] = byte(c[6] | c[7]<<4)
+}
+
+func encode5(b *[5]byte, c *[8]uint16) {
+	b[0] = byte(c[0] | c[1]<<5)
+	b[1] = byte(c[1]>>3 | c[2]<<2 | c[3]<<7)
+	b[2] = byte(c[3]>>1 | c[4]<<4)
+	b[3] = byte(c[4]>>4 | c[5]<<1 | c[6]<<6)
+	b[4] = byte(c[6]>>2 | c[7]<<3)
+}
+
+func encode10(b *[10]byte, c *[8]uint16) {
+	b[0] = byte(c[0])
+	b[1] = byte(c[0]>>8 | c[1]<<2)
+	b[2] = byte(c[1]>>6 | c[2]<<4)
+	b[3] = byte(c[2]>>4 | c[3]<<6)
+	b[4] = byte(c[3] >> 2)
+	b[5] = byte(c[4])
+	b[6] = byte(c[4]>>8 | c[5]<<2)
+	b[7] = byte(c[5]>>6 | c[6]<<4)
+	b[8] = byte(c[6]>>4 | c[7]<<6)
+	b[9] = byte(c[7] >> 2)
+}
+
+func encode11(b *[11]byte, c *[8]uint16) {
+	b[0] = byte(c[0])
+	b[1] = byte(c[0]>>8 | c[1]<<3)
+	b[2] = byte(c[1]>>5 | c[2]<<6)
+	b[3] = byte(c[2] >> 2)
+	b[4] = byte(c[2]>>10 | c[3]<<1)
+	b[5] = byte(c[3]>>7 | c[4]<<4)
+	b[6] = byte(c[4]>>4 | c[5]<<7)
+	b[7] = byte(c[5] >> 1)
+	b[8] = byte(c[5]>>9 | c[6]<<2)
+	b[9] = byte(c[6]>>6 | c[7]<<5)
+	b[10] = byte(c[7] >> 3)
+}
+
+func encode12(b *[12]byte, c *[8]uint16) {
+	for i := range 4 {
+		x, y := c[2*i], c[2*i+1]
+		b[3*i] = byte(x)
+		b[3*i+1] = byte(x>>8 | y<<4)
+		b[3*i+2] = byte(y >> 4)
+	}
+}
+
+// decode8 sets c to the 8 d-bit values decoded from d bytes b.
+func decode8(c *[8]uint16, b []byte, d int) {
+	switch d {
+	case 1:
+		decode1(c, (*[1]byte)(b))
+	case 4:
+		decode4(c, (*[4]byte)(b))
+	case 5:
+		decode5(c, (*[5]byte)(b))
+	case 10:
+		decode10(c, (*[10]byte)(b))
+	case 11:
+		decode11(c, (*[11]byte)(b))
+	case 12:
+		decode12(c, (*[12]byte)(b))
+	default:
+		byteDecodeGeneric(c[:], b[:d], d)
+	}
+}
+
+func decode1(c *[8]uint16, b *[1]byte) {
+	x := uint16(b[0])
+	for i := range c {
+		c[i] = x >> i & 1
+	}
+}
+
+func decode4(c *[8]uint16, b *[4]byte) {
+	for i := range 4 {
+		c[2*i] = uint16(b[i] & 0xf)
+		c[2*i+1] = uint16(b[i] >> 4)
+	}
+}
+
+func decode5(c *[8]uint16, b *[5]byte) {
+	c[0] = uint16(b[0]) & 0x1f
+	c[1] = (uint16(b[0])>>5 | uint16(b[1])<<3) & 0x1f
+	c[2] = uint16(b[1]) >> 2 & 0x1f
+	c[3] = (uint16(b[1])>>7 | uint16(b[2])<<1) & 0x1f
+	c[4] = (uint16(b[2])>>4 | uint16(b[3])<<4) & 0x1f
+	c[5] = uint16(b[3]) >> 1 & 0x1f
+	c[6] = (uint16(b[3])>>6 | uint16(b[4])<<2) & 0x1f
+	c[7] = uint16(b[4]) >> 3
+}
+
+func decode10(c *[8]uint16, b *[10]byte) {
+	c[0] = (uint16(b[0]) | uint16(b[1])<<8) & 0x3ff
+	c[1] = (uint16(b[1])>>2 | uint16(b[2])<<6) & 0x3ff
+	c[2] = (uint16(b[2])>>4 | uint16(b[3])<<4) & 0x3ff
+	c[3] = uint16(b[3])>>6 | uint16(b[4])<<2
+	c[4] = (uint16(b[5]) | uint16(b[6])<<8) & 0x3ff
+	c[5] = (uint16(b[6])>>2 | uint16(b[7])<<6) & 0x3ff
+	c[6] = (uint16(b[7])>>4 | uint16(b[8])<<4) & 0x3ff
+	c[7] = uint16(b[8])>>6 | uint16(b[9])<<2
+}
+
+func decode11(c *[8]uint16, b *[11]byte) {
+	c[0] = (uint16(b[0]) | uint16(b[1])<<8) & 0x7ff
+	c[1] = (uint16(b[1])>>3 | uint16(b[2])<<5) & 0x7ff
+	c[2] = (uint16(b[2])>>6 | uint16(b[3])<<2 | uint16(b[4])<<10) & 0x7ff
+	c[3] = (uint16(b[4])>>1 | uint16(b[5])<<7) & 0x7ff
+	c[4] = (uint16(b[5])>>4 | uint16(b[6])<<4) & 0x7ff
+	c[5] = (uint16(b[6])>>7 | uint16(b[7])<<1 | uint16(b[8])<<9) & 0x7ff
+	c[6] = (uint16(b[8])>>2 | uint16(b[9])<<6) & 0x7ff
+	c[7] = uint16(b[9])>>5 | uint16(b[10])<<3
+}
+
+func decode12(c *[8]uint16, b *[12]byte) {
+	for i := range 4 {
+		c[2*i] = (uint16(b[3*i]) | uint16(b[3*i+1])<<8) & 0xfff
+		c[2*i+1] = uint16(b[3*i+1])>>4 | uint16(b[3*i+2])<<4
+	}
+}
+
+// byteEncodeGeneric writes the encoding of the d-bit values f to b one bit at a time.
+func byteEncodeGeneric(b []byte, f []uint16, d int) {
+	clear(b[:len(f)*d/8])
+	for i, a := range f {
+		for j := range d {
+			setBit(b, i*d+j, int((a>>j)&1))
+		}
+	}
+}
+
+// byteDecodeGeneric sets f to the d-bit values decoded from b one bit at a time.
+func byteDecodeGeneric(f []uint16, b []byte, d int) {
+	for i := range f {
+		var a uint16
+		for j := range d {
+			a |= uint16(getBit(b, i*d+j) << j)
+		}
+		f[i] = a
+	}
+}
+
+// byteEncodeQ writes the 384-byte encoding of f to b.
+func (f *polynomial) byteEncodeQ(b []byte) {
+	for i := 0; i < len(f); i += 8 {
+		var c [8]uint16
+		for j := range c {
+			c[j] = uint16(f[i+j])
+		}
+		encode8(b[i/8*12:], &c, 12)
+	}
+}
+
+// byteDecodeQ sets f to the polynomial decoded from 384 bytes b.
+func (f *polynomial) byteDecodeQ(b []byte) {
+	for i := 0; i < len(f); i += 8 {
+		var c [8]uint16
+		decode8(&c, b[i/8*12:], 12)
+		for j, x := range c {
+			f[i+j] = reduceOnce(int16(x))
+		}
+	}
+}
+
+// compressEncodeGeneric writes ByteEncode_d(Compress_d(f)) to b,
+// compressing and packing 8 coefficients at a time.
+func (f *polynomial) compressEncodeGeneric(b []byte, d int) {
+	for i := 0; i < len(f); i += 8 {
+		var c [8]uint16
+		for j := range c {
+			c[j] = uint16(compress(f[i+j], d))
+		}
+		encode8(b[i/8*d:], &c, d)
+	}
+}
+
+// decodeDecompress sets f to Decompress_d(ByteDecode_d(b)),
+// unpacking and decompressing 8 coefficients at a time.
+func (f *polynomial) decodeDecompress(b []byte, d int) {
+	for i := 0; i < len(f); i += 8 {
+		var c [8]uint16
+		decode8(&c, b[i/8*d:], d)
+		for j, y := range c {
+			f[i+j] = decompress(y, d)
+		}
+	}
+}
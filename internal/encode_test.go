@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+)
+
+var encodeWidths = []int{1, 4, 5, 10, 11, 12}
+
+func TestByteEncodeSpecialized(t *testing.T) {
+	for _, d := range encodeWidths {
+		t.Run(fmt.Sprintf("d=%d", d), func(t *testing.T) {
+			mask := uint16(1<<d - 1)
+			// Every value at every position of a group.
+			for v := range uint16(1 << d) {
+				var f compressed
+				for i := range f {
+					f[i] = (v + 37*uint16(i)) & mask
+				}
+				b1, b2 := make([]byte, 32*d), make([]byte, 32*d)
+				byteEncode(b1, f[:], d)
+				byteEncodeGeneric(b2, f[:], d)
+				if string(b1) != string(b2) {
+					t.Fatalf("encoding mismatch for %d", v)
+				}
+			}
+			// Every byte value at every position of a group.
+			for x := range 256 {
+				b := make([]byte, 32*d)
+				for i := range b {
+					b[i] = byte(x + 29*i)
+				}
+				var f1, f2 compressed
+				byteDecode(f1[:], b, d)
+				byteDecodeGeneric(f2[:], b, d)
+				if f1 != f2 {
+					t.Fatalf("decoding mismatch for %d", x)
+				}
+			}
+		})
+	}
+}
+
+func TestCompressEncode(t *testing.T) {
+	for _, d := range []int{1, 4, 5, 10, 11} {
+		t.Run(fmt.Sprintf("d=%d", d), func(t *testing.T) {
+			f := func(f polynomial) bool {
+				var c compressed
+				polyCompressGeneric(&c, &f, d)
+				b1, b2 := make([]byte, 32*d), make([]byte, 32*d)
+				f.compressEncode(b1, d)
+				byteEncodeGeneric(b2, c[:], d)
+				if string(b1) != string(b2) {
+					return false
+				}
+				var g polynomial
+				g.decodeDecompress(b1, d)
+				return g == Decompress(Compress(f, d), d)
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+			var all polynomial
+			for x := 0; x < q; x += 256 {
+				for i := range all {
+					all[i] = int16(min(x+i, q-1))
+				}
+				if !f(all) {
+					t.Fatalf("mismatch for %d", x)
+				}
+			}
+		})
+	}
+}
+
+func TestByteEncodeQSpecialized(t *testing.T) {
+	f := func(f polynomial) bool {
+		var c compressed
+		for i := range f {
+			c[i] = uint16(f[i])
+		}
+		b1, b2 := make([]byte, 384), make([]byte, 384)
+		f.byteEncodeQ(b1)
+		byteEncodeGeneric(b2, c[:], 12)
+		return string(b1) == string(b2)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkByteEncode(b *testing.B) {
+	for _, d := range encodeWidths {
+		b.Run(fmt.Sprintf("d=%d", d), func(b *testing.B) {
+			var f compressed
+			buf := make([]byte, 32*d)
+			for b.Loop() {
+				byteEncode(buf, f[:], d)
+			}
+		})
+	}
+}
+
+func BenchmarkByteDecode(b *testing.B) {
+	for _, d := range encodeWidths {
+		b.Run(fmt.Sprintf("d=%d", d), func(b *testing.B) {
+			var f compressed
+			buf := make([]byte, 32*d)
+			for b.Loop() {
+				byteDecode(f[:], buf, d)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+// Field arithmetic modulo q on signed 16-bit coefficients.
+//
+// Multiplication uses Montgomery reduction with R = 2¹⁶: fqmul(a, b) = a·b·R⁻¹ mod q,
+// so constants multiplied by fqmul are stored in Montgomery form (multiplied by R).
+// Additions and subtractions are not reduced where the bounds allow it (lazy reduction)
+// and values are brought back to the canonical range [0, q) before they leave the ring core.
+
+const (
+	qInv   = -3327 // q⁻¹ mod 2¹⁶
+	rModQ  = 2285  // R mod q
+	r2ModQ = 1353  // R² mod q
+)
+
+// montgomeryReduce returns a·R⁻¹ mod q in (-q, q) for -q·2¹⁵ ≤ a < q·2¹⁵.
+func montgomeryReduce(a int32) int16 {
+	t := int16(a) * qInv
+	return int16((a - int32(t)*q) >> 16)
+}
+
+// fqmul returns a·b·R⁻¹ mod q in (-q, q).
+func fqmul(a, b int16) int16 {
+	return montgomeryReduce(int32(a) * int32(b))
+}
+
+// barrettReduce returns a mod q in the centered range [-(q-1)/2, (q-1)/2].
+func barrettReduce(a int16) int16 {
+	const v = ((1 << 26) + q/2) / q
+	t := int16((v*int32(a) + (1 << 25)) >> 26)
+	return a - t*q
+}
+
+// reduceOnce returns a mod q for 0 ≤ a < 2q.
+func reduceOnce(a int16) int16 {
+	return condAddQ(a - q)
+}
+
+// condAddQ returns a mod q for -q ≤ a < q, adding q if a is negative.
+func condAddQ(a int16) int16 {
+	return a + (q & (a >> 15))
+}
+
+// canonical returns a mod q in [0, q) for any a.
+func canonical(a int16) int16 {
+	return condAddQ(barrettReduce(a))
+}
+
+// zetasMont[i] = ζ^BitRev₇(i)·R mod q, centered.
+var zetasMont = [128]int16{
+	-1044, -758, -359, -1517, 1493, 1422, 287, 202,
+	-171, 622, 1577, 182, 962, -1202, -1474, 1468,
+	573, -1325, 264, 383, -829, 1458, -1602, -130,
+	-681, 1017, 732, 608, -1542, 411, -205, -1571,
+	1223, 652, -552, 1015, -1293, 1491, -282, -1544,
+	516, -8, -320, -666, -1618, -1162, 126, 1469,
+	-853, -90, -271, 830, 107, -1421, -247, -951,
+	-398, 961, -1508, -725, 448, -1065, 677, -1275,
+	-1103, 430, 555, 843, -1251, 871, 1550, 105,
+	422, 587, 177, -235, -291, -460, 1574, 1653,
+	-246, 778, 1159, -147, -777, 1483, -602, 1119,
+	-1590, 644, -872, 349, 418, 329, -156, -75,
+	817, 1097, 603, 610, 1322, -1285, -1465, 384,
+	-1215, -136, 1218, -1335, -874, 220, -1187, -1659,
+	-1185, -1530, -1278, 794, -1510, -854, -870, 478,
+	-108, -308, 996, 991, 958, -1460, 1522, 1628,
+}
+
+// gammasMont[i] = ζ^(2·BitRev₇(i)+1)·R mod q, centered.
+var gammasMont = [128]int16{
+	-1103, 1103, 430, -430, 555, -555, 843, -843,
+	-1251, 1251, 871, -871, 1550, -1550, 105, -105,
+	422, -422, 587, -587, 177, -177, -235, 235,
+	-291, 291, -460, 460, 1574, -1574, 1653, -1653,
+	-246, 246, 778, -778, 1159, -1159, -147, 147,
+	-777, 777, 1483, -1483, -602, 602, 1119, -1119,
+	-1590, 1590, 644, -644, -872, 872, 349, -349,
+	418, -418, 329, -329, -156, 156, -75, 75,
+	817, -817, 1097, -1097, 603, -603, 610, -610,
+	1322, -1322, -1285, 1285, -1465, 1465, 384, -384,
+	-1215, 1215, -136, 136, 1218, -1218, -1335, 1335,
+	-874, 874, 220, -220, -1187, 1187, -1659, 1659,
+	-1185, 1185, -1530, 1530, -1278, 1278, 794, -794,
+	-1510, 1510, -854, 854, -870, 870, 478, -478,
+	-108, 108, -308, 308, 996, -996, 991, -991,
+	958, -958, -1460, 1460, 1522, -1522, 1628, -1628,
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Keccak-f[1600] applied to four independent states at once, used to generate
+// four entries of the matrix Â in parallel.
+//
+// Lane i of state n is stored at s[i][n] so that the four copies of a lane are adjacent
+// and can be processed as one vector.
+type keccak4 [25][4]uint64
+
+const shake128Rate = 168
+
+var keccakRC = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRho and keccakPi list the rotation offsets and the destination lanes
+// of the combined ρ and π steps, starting from lane 1.
+var (
+	keccakRho = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	keccakPi  = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+func (s *keccak4) permuteGeneric() {
+	for _, rc := range keccakRC {
+		// θ
+		var c [5][4]uint64
+		for x := range 5 {
+			for n := range 4 {
+				c[x][n] = s[x][n] ^ s[x+5][n] ^ s[x+10][n] ^ s[x+15][n] ^ s[x+20][n]
+			}
+		}
+		for x := range 5 {
+			for n := range 4 {
+				d := c[(x+4)%5][n] ^ bits.RotateLeft64(c[(x+1)%5][n], 1)
+				for y := 0; y < 25; y += 5 {
+					s[x+y][n] ^= d
+				}
+			}
+		}
+		// ρ and π
+		t := s[1]
+		for i, j := range keccakPi {
+			u := s[j]
+			for n := range 4 {
+				s[j][n] = bits.RotateLeft64(t[n], keccakRho[i])
+			}
+			t = u
+		}
+		// χ
+		for y := 0; y < 25; y += 5 {
+			b := [5][4]uint64(s[y : y+5])
+			for x := range 5 {
+				for n := range 4 {
+					s[y+x][n] = b[x][n] ^ (^b[(x+1)%5][n] & b[(x+2)%5][n])
+				}
+			}
+		}
+		// ι
+		for n := range 4 {
+			s[0][n] ^= rc
+		}
+	}
+}
+
+// initSHAKE128 absorbs the 34-byte inputs ρ‖j‖i of the four SHAKE128 instances of SampleNTT.
+func (s *keccak4) initSHAKE128(ro []byte, ji [4][2]byte) {
+	*s = keccak4{}
+	for n := range 4 {
+		for i := range 4 {
+			s[i][n] = binary.LittleEndian.Uint64(ro[8*i:])
+		}
+		s[4][n] = uint64(ji[n][0]) | uint64(ji[n][1])<<8 | 0x1f<<16 // SHAKE padding
+		s[shake128Rate/8-1][n] = 0x80 << 56
+	}
+}
+
+// block writes the first rate bytes of state n to b.
+func (s *keccak4) block(b *[shake128Rate]byte, n int) {
+	for i := range shake128Rate / 8 {
+		binary.LittleEndian.PutUint64(b[8*i:], s[i][n])
+	}
+}
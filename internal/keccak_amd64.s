@@ -0,0 +1,115 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// Keccak-f[1600]×4 on the interleaved state of keccak.go:
+// each 32-byte row of the state holds one lane of the four instances.
+
+// ROTL sets dst = x <<< n, using t as scratch.
+#define ROTL(n, x, t, dst) \
+	VPSLLQ $n, x, t;        \
+	VPSRLQ $(64-n), x, dst; \
+	VPOR   t, dst, dst
+
+// THETAC sets c to the parity of column x.
+#define THETAC(x, c) \
+	VMOVDQU (x*32)(DI), c;      \
+	VPXOR   ((x+5)*32)(DI), c, c;  \
+	VPXOR   ((x+10)*32)(DI), c, c; \
+	VPXOR   ((x+15)*32)(DI), c, c; \
+	VPXOR   ((x+20)*32)(DI), c, c
+
+// THETAD sets d = cl ^ (cr <<< 1).
+#define THETAD(cl, cr, d) \
+	ROTL(1, cr, Y10, d); \
+	VPXOR cl, d, d
+
+// RHOPI moves the lane t, rotated by n, to lane j,
+// and loads lane j with the θ effect d applied into u.
+#define RHOPI(j, d, n, t, u) \
+	VPXOR   (j*32)(DI), d, u; \
+	ROTL(n, t, Y12, Y13);     \
+	VMOVDQU Y13, (j*32)(DI)
+
+// CHI applies χ to the row starting at lane y.
+#define CHI(y) \
+	VMOVDQU ((y+0)*32)(DI), Y0; \
+	VMOVDQU ((y+1)*32)(DI), Y1; \
+	VMOVDQU ((y+2)*32)(DI), Y2; \
+	VMOVDQU ((y+3)*32)(DI), Y3; \
+	VMOVDQU ((y+4)*32)(DI), Y4; \
+	CHILANE(y+0, Y0, Y1, Y2);   \
+	CHILANE(y+1, Y1, Y2, Y3);   \
+	CHILANE(y+2, Y2, Y3, Y4);   \
+	CHILANE(y+3, Y3, Y4, Y0);   \
+	CHILANE(y+4, Y4, Y0, Y1)
+
+#define CHILANE(i, b0, b1, b2) \
+	VPANDN  b2, b1, Y10; \
+	VPXOR   b0, Y10, Y10; \
+	VMOVDQU Y10, ((i)*32)(DI)
+
+// func keccakF1600x4AVX2(s *keccak4)
+TEXT ·keccakF1600x4AVX2(SB), NOSPLIT, $0-8
+	MOVQ s+0(FP), DI
+	LEAQ ·keccakRC(SB), SI
+	MOVQ $24, CX
+
+round:
+	// θ: Y0-Y4 hold the column parities and Y5-Y9 the values added to each column.
+	THETAC(0, Y0)
+	THETAC(1, Y1)
+	THETAC(2, Y2)
+	THETAC(3, Y3)
+	THETAC(4, Y4)
+	THETAD(Y4, Y1, Y5)
+	THETAD(Y0, Y2, Y6)
+	THETAD(Y1, Y3, Y7)
+	THETAD(Y2, Y4, Y8)
+	THETAD(Y3, Y0, Y9)
+
+	// ρ and π, with θ applied as the lanes are loaded.
+	VPXOR   (DI), Y5, Y0
+	VMOVDQU Y0, (DI)
+	VPXOR   32(DI), Y6, Y11
+	RHOPI(10, Y5, 1, Y11, Y14)
+	RHOPI(7, Y7, 3, Y14, Y11)
+	RHOPI(11, Y6, 6, Y11, Y14)
+	RHOPI(17, Y7, 10, Y14, Y11)
+	RHOPI(18, Y8, 15, Y11, Y14)
+	RHOPI(3, Y8, 21, Y14, Y11)
+	RHOPI(5, Y5, 28, Y11, Y14)
+	RHOPI(16, Y6, 36, Y14, Y11)
+	RHOPI(8, Y8, 45, Y11, Y14)
+	RHOPI(21, Y6, 55, Y14, Y11)
+	RHOPI(24, Y9, 2, Y11, Y14)
+	RHOPI(4, Y9, 14, Y14, Y11)
+	RHOPI(15, Y5, 27, Y11, Y14)
+	RHOPI(23, Y8, 41, Y14, Y11)
+	RHOPI(19, Y9, 56, Y11, Y14)
+	RHOPI(13, Y8, 8, Y14, Y11)
+	RHOPI(12, Y7, 25, Y11, Y14)
+	RHOPI(2, Y7, 43, Y14, Y11)
+	RHOPI(20, Y5, 62, Y11, Y14)
+	RHOPI(14, Y9, 18, Y14, Y11)
+	RHOPI(22, Y7, 39, Y11, Y14)
+	RHOPI(9, Y9, 61, Y14, Y11)
+	RHOPI(6, Y6, 20, Y11, Y14)
+	RHOPI(1, Y6, 44, Y14, Y11)
+
+	// χ and ι
+	CHI(0)
+	CHI(5)
+	CHI(10)
+	CHI(15)
+	CHI(20)
+	VPBROADCASTQ (SI), Y0
+	VPXOR        (DI), Y0, Y0
+	VMOVDQU      Y0, (DI)
+
+	ADDQ $8, SI
+	DECQ CX
+	JNZ  round
+
+	VZEROUPPER
+	RET
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"crypto/sha3"
+	"testing"
+	"testing/quick"
+)
+
+func TestKeccak4(t *testing.T) {
+	t.Run("SHAKE128", func(t *testing.T) {
+		f := func(ro [32]byte, ji [4][2]byte) bool {
+			var s keccak4
+			s.initSHAKE128(ro[:], ji)
+			var got [4][3 * shake128Rate]byte
+			for i := range 3 {
+				s.permute()
+				for n := range 4 {
+					s.block((*[shake128Rate]byte)(got[n][i*shake128Rate:]), n)
+				}
+			}
+			for n := range 4 {
+				xof := sha3.NewSHAKE128()
+				xof.Write(ro[:])
+				xof.Write(ji[n][:])
+				var want [3 * shake128Rate]byte
+				xof.Read(want[:])
+				if got[n] != want {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("sampleNTTx4", func(t *testing.T) {
+		f := func(ro [32]byte, ji [4][2]byte) bool {
+			var a [4]polynomial
+			sampleNTTx4(&[4]*polynomial{&a[0], &a[1], nil, &a[3]}, ro[:], ji)
+			for n := range a {
+				var want polynomial
+				if n != 2 {
+					want = SampleNTT(ro[:], ji[n][0], ji[n][1])
+				}
+				if a[n] != want {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+}
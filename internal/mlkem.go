@@ -1,107 +1,137 @@
 package internal
 
 import (
-	"bytes"
 	"crypto/sha3"
+	"crypto/subtle"
+	"encoding/binary"
 )
 
 const q = 3329
 
+// maxK is the largest module rank k of the ML-KEM parameter sets.
+// Keys and temporaries use fixed-size arrays of this size so that they do not need heap allocations.
+const maxK = 4
+
 type (
-	uintq      uint16
 	uintq2     uint32
-	intq2      int32 // can store range [-2*q^2, 2*q^2]
-	polynomial [256]uintq
+	polynomial [256]int16 // coefficients are in the canonical range [0, q)
 )
 
-func add(a, b polynomial) polynomial {
-	var c polynomial
-	for i := range a {
-		c[i] = (a[i] + b[i]) % q
+// add sets f to f + g.
+func (f *polynomial) add(g *polynomial) {
+	for i := range f {
+		f[i] = reduceOnce(f[i] + g[i])
 	}
-	return c
 }
 
-func sub(a, b polynomial) polynomial {
-	var c polynomial
-	for i := range a {
-		c[i] = (q + a[i] - b[i]) % q
+// sub sets f to f − g.
+func (f *polynomial) sub(g *polynomial) {
+	for i := range f {
+		f[i] = condAddQ(f[i] - g[i])
 	}
-	return c
 }
 
-func vectorAdd(a, b []polynomial) []polynomial {
-	k := len(a)
-	c := make([]polynomial, k)
-	for i := range k {
-		c[i] = add(a[i], b[i])
-	}
-	return c
+func add(a, b polynomial) polynomial {
+	a.add(&b)
+	return a
 }
 
-func NTT(f polynomial) polynomial {
-	f_ := f
+func sub(a, b polynomial) polynomial {
+	a.sub(&b)
+	return a
+}
+
+// nttGeneric computes NTT(f) in place.
+func (f *polynomial) nttGeneric() {
 	i := 1
 	for len := 128; len >= 2; len /= 2 {
 		for start := 0; start < 256; start += 2 * len {
-			zeta := zetaBitRev7[i]
+			zeta := zetasMont[i]
 			i++
-			for j := start; j < start+len; j++ {
-				t := uintq(zeta * uintq2(f_[j+len]) % q)
-				f_[j+len] = (q + f_[j] - t) % q
-				f_[j] = (f_[j] + t) % q
+			a, b := f[start:start+len], f[start+len:start+2*len]
+			for j := range a {
+				// Coefficients grow by less than q per layer and stay below 8q < 2¹⁵.
+				t := fqmul(zeta, b[j])
+				b[j] = a[j] - t
+				a[j] = a[j] + t
 			}
 		}
 	}
-	return f_
+	for i := range f {
+		f[i] = canonical(f[i])
+	}
 }
 
-func NTTinv(f_ polynomial) polynomial {
-	f := f_
+// nttInvGeneric computes NTT⁻¹(f) in place.
+// The coefficients of f may be in (-q, q).
+func (f *polynomial) nttInvGeneric() {
+	const f128 = 512 // 128⁻¹·R mod q
 	i := 127
 	for len := 2; len <= 128; len *= 2 {
 		for start := 0; start < 256; start += 2 * len {
-			zeta := zetaBitRev7[i]
+			zeta := zetasMont[i]
 			i--
-			for j := start; j < start+len; j++ {
-				t := f[j]
-				f[j] = (t + f[j+len]) % q
-				f[j+len] = uintq(zeta * uintq2(q+f[j+len]-t) % q)
+			a, b := f[start:start+len], f[start+len:start+2*len]
+			for j := range a {
+				t := a[j]
+				a[j] = barrettReduce(t + b[j])
+				b[j] = fqmul(zeta, b[j]-t)
 			}
 		}
 	}
 	for i := range f {
-		f[i] = uintq(uintq2(f[i]) * 3303 % q) // multiply every entry by 3303 == 128^−1 mod q
+		f[i] = condAddQ(fqmul(f[i], f128)) // multiply every entry by 128⁻¹ mod q
 	}
+}
+
+func NTT(f polynomial) polynomial {
+	f.ntt()
 	return f
 }
 
-func vectorNTTinv(f_ []polynomial) []polynomial {
-	k := len(f_)
-	g := make([]polynomial, k)
-	for i := range k {
-		g[i] = NTTinv(f_[i])
+func NTTinv(f_ polynomial) polynomial {
+	f_.nttInv()
+	return f_
+}
+
+// multiplyAccGeneric adds f̂ × ĝ multiplied by R⁻¹ to h, which accumulates up to maxK products
+// without reduction and must be finished with [polynomial.reduceAccGeneric].
+func (h *polynomial) multiplyAccGeneric(f, g *polynomial) {
+	for i := range 128 {
+		c0, c1 := BaseCaseMultiply(f[2*i], f[2*i+1], g[2*i], g[2*i+1], gammasMont[i])
+		h[2*i] += c0
+		h[2*i+1] += c1
+	}
+}
+
+// reduceAccGeneric brings the coefficients accumulated by [polynomial.multiplyAcc]
+// to the canonical range.
+func (h *polynomial) reduceAccGeneric() {
+	for i := range h {
+		// The accumulated products carry a factor of R⁻¹, multiplying by R² mod q removes it.
+		h[i] = condAddQ(fqmul(h[i], r2ModQ))
 	}
-	return g
 }
 
 func MultiplyNTTs(f_, g_ polynomial) polynomial {
 	var h_ polynomial
-	for i := range 128 {
-		h_[2*i], h_[2*i+1] = BaseCaseMultiply(f_[2*i], f_[2*i+1], g_[2*i], g_[2*i+1], zeta2BitRev7[i])
-	}
+	h_.multiplyAcc(&f_, &g_)
+	h_.reduceAcc()
 	return h_
 }
 
-func BaseCaseMultiply(a0, a1, b0, b1 uintq, g intq2) (uintq, uintq) {
-	a0_, a1_, b0_, b1_ := intq2(a0), intq2(a1), intq2(b0), intq2(b1)
-	c0_ := a0_*b0_ + ((a1_*b1_)%q)*g
-	c1_ := a0_*b1_ + a1_*b0_
-	return uintq((c0_ + q*q) % q), uintq(c1_ % q)
+// BaseCaseMultiply computes (a₀ + a₁X)(b₀ + b₁X) mod (X² − γ) where g = γ·R mod q
+// and the coefficients are in the canonical range.
+// The result is multiplied by R⁻¹ and lies in (-q, q).
+func BaseCaseMultiply(a0, a1, b0, b1, g int16) (int16, int16) {
+	// fqmul(a₁, b₁)·g ≡ a₁b₁γ mod q, the sums stay below 1.5q² < q·2¹⁵.
+	c0 := montgomeryReduce(int32(a0)*int32(b0) + int32(fqmul(a1, b1))*int32(g))
+	c1 := montgomeryReduce(int32(a0)*int32(b1) + int32(a1)*int32(b0))
+	return c0, c1
 }
 
-func SamplePolyCBD(b []byte) polynomial {
-	var f polynomial
+// samplePolyCBDGeneric sets f to the polynomial sampled from 64η bytes b.
+func (f *polynomial) samplePolyCBDGeneric(b []byte) {
 	eta := len(b) / 64
 	for i := range 256 {
 		var x, y int
@@ -109,22 +139,124 @@ func SamplePolyCBD(b []byte) polynomial {
 			x += getBit(b, 2*i*eta+j)
 			y += getBit(b, 2*i*eta+eta+j)
 		}
-		f[i] = uintq((q + x - y) % q)
+		f[i] = condAddQ(int16(x - y))
+	}
+}
+
+// samplePolyCBDBitsliced is like samplePolyCBDGeneric but for η = 2 and η = 3
+// adds up the η-bit groups of a whole word at once.
+func (f *polynomial) samplePolyCBDBitsliced(b []byte) {
+	switch len(b) {
+	case 64 * 2:
+		for i := range 4 {
+			cbd2((*[64]int16)(f[64*i:]), (*[32]byte)(b[32*i:]))
+		}
+	case 64 * 3:
+		for i := range 4 {
+			cbd3((*[64]int16)(f[64*i:]), (*[48]byte)(b[48*i:]))
+		}
+	default:
+		f.samplePolyCBDGeneric(b)
 	}
+}
+
+// samplePolyCBDPRFGeneric sets f to SamplePolyCBD_η(PRF_η(s, b))
+// reading the PRF output in 16η-byte chunks of 64 coefficients.
+func (f *polynomial) samplePolyCBDPRFGeneric(s []byte, b byte, eta int) {
+	h := sha3.NewSHAKE256()
+	h.Write(s)
+	h.Write([]byte{b})
+	var c [16 * 3]byte
+	for i := 0; i < 256; i += 64 {
+		h.Read(c[:16*eta])
+		switch eta {
+		case 2:
+			cbd2((*[64]int16)(f[i:]), (*[32]byte)(c[:]))
+		case 3:
+			cbd3((*[64]int16)(f[i:]), &c)
+		default:
+			panic("mlkem: unsupported η")
+		}
+	}
+	clear(c[:])
+	h.Reset()
+}
+
+// cbd2 sets f to the coefficients sampled with η = 2 from b.
+// A 32-bit word holds 8 coefficients: the 2-bit sums of adjacent bits are computed at once
+// and each coefficient is the difference of two consecutive sums.
+func cbd2(f *[64]int16, b *[32]byte) {
+	for i := range 8 {
+		w := binary.LittleEndian.Uint32(b[4*i:])
+		t := w&0x55555555 + w>>1&0x55555555
+		for j := range 8 {
+			x := int16(t >> (4 * j) & 3)
+			y := int16(t >> (4*j + 2) & 3)
+			f[8*i+j] = condAddQ(x - y)
+		}
+	}
+}
+
+// cbd3 sets f to the coefficients sampled with η = 3 from b.
+// A 24-bit word holds 4 coefficients: the 3-bit sums of groups of 3 bits are computed at once
+// and each coefficient is the difference of two consecutive sums.
+func cbd3(f *[64]int16, b *[48]byte) {
+	for i := range 16 {
+		w := uint32(b[3*i]) | uint32(b[3*i+1])<<8 | uint32(b[3*i+2])<<16
+		t := w&0x249249 + w>>1&0x249249 + w>>2&0x249249
+		for j := range 4 {
+			x := int16(t >> (6 * j) & 7)
+			y := int16(t >> (6*j + 3) & 7)
+			f[4*i+j] = condAddQ(x - y)
+		}
+	}
+}
+
+func SamplePolyCBD(b []byte) polynomial {
+	var f polynomial
+	f.samplePolyCBD(b)
 	return f
 }
 
-func SampleNTT(b []byte, jj, ii byte) polynomial {
-	var a polynomial
+// sampleNTT sets a to the polynomial sampled from the 32-byte seed b and the indices j and i.
+func (a *polynomial) sampleNTT(b []byte, jj, ii byte) {
 	xof := sha3.NewSHAKE128()
 	xof.Write(b)
 	xof.Write([]byte{jj, ii})
-	var c [3]byte
-	j := 0
-	for j < 256 {
+	var c [shake128Rate]byte
+	for j := 0; j < 256; {
 		xof.Read(c[:])
-		d1 := uintq(c[0]) + 256*uintq(c[1]%16)
-		d2 := uintq(c[1]/16) + 16*uintq(c[2])
+		j = a.rejectNTT(j, c[:])
+	}
+}
+
+// sampleNTTx4 is like sampleNTT but samples up to four polynomials at once
+// from the seed b and the index pairs ji, skipping nil entries of a.
+func sampleNTTx4(a *[4]*polynomial, b []byte, ji [4][2]byte) {
+	var s keccak4
+	s.initSHAKE128(b, ji)
+	var c [shake128Rate]byte
+	var j [4]int
+	for done := false; !done; {
+		s.permute()
+		done = true
+		for n, f := range a {
+			if f == nil || j[n] == 256 {
+				continue
+			}
+			s.block(&c, n)
+			j[n] = f.rejectNTT(j[n], c[:])
+			done = done && j[n] == 256
+		}
+	}
+}
+
+// rejectNTT parses the XOF output b into coefficients of a starting from a[j]
+// as in SampleNTT lines 5-14 and returns the number of coefficients sampled so far.
+func (a *polynomial) rejectNTT(j int, b []byte) int {
+	for ; len(b) >= 3 && j < 256; b = b[3:] {
+		d1 := int16(b[0]) | int16(b[1]&0xf)<<8
+		d2 := int16(b[1]>>4) | int16(b[2])<<4
 		if d1 < q {
 			a[j] = d1
 			j++
@@ -134,270 +266,492 @@ func SampleNTT(b []byte, jj, ii byte) polynomial {
 			j++
 		}
 	}
+	return j
+}
+
+func SampleNTT(b []byte, jj, ii byte) polynomial {
+	var a polynomial
+	a.sampleNTT(b, jj, ii)
 	return a
 }
 
 // G(𝑑, 𝑘) ∶= SHA3-512(𝑑‖𝑘)
-func G(d []byte, k []byte) ([]byte, []byte) {
+func G(d []byte, k []byte) (a, b [32]byte) {
 	g := sha3.New512()
 	g.Write(d)
 	g.Write(k)
-	b := g.Sum(nil)
-	return b[:32], b[32:]
+	var s [64]byte
+	g.Sum(s[:0])
+	copy(a[:], s[:32])
+	copy(b[:], s[32:])
+	clear(s[:])
+	g.Reset()
+	return a, b
 }
 
 // H(𝑠) ∶= SHA3-256(𝑠)
-func H(s []byte) []byte {
-	h := sha3.New256()
-	h.Write(s)
-	return h.Sum(nil)
+func H(s []byte) [32]byte {
+	return sha3.Sum256(s)
 }
 
 // J(𝑠, 𝑡) ∶= SHAKE256(𝑠‖𝑡, 8 ⋅ 32)
-func J(s, t []byte) []byte {
+func J(s, t []byte) [32]byte {
 	h := sha3.NewSHAKE256()
 	h.Write(s)
 	h.Write(t)
-	r := make([]byte, 32)
-	h.Read(r)
+	var r [32]byte
+	h.Read(r[:])
+	h.Reset()
 	return r
 }
 
 // PRF𝜂(𝑠, 𝑏) ∶= SHAKE256(𝑠‖𝑏, 8 ⋅ 64 ⋅ 𝜂)
-func PRF(s []byte, b byte, eta int) []byte {
+//
+// The output is written to r which must be 64η bytes long.
+func PRF(r []byte, s []byte, b byte) {
 	h := sha3.NewSHAKE256()
 	h.Write(s)
 	h.Write([]byte{b})
-	r := make([]byte, 64*eta)
 	h.Read(r)
-	return r
+	h.Reset()
+}
+
+// EncryptionKey is a parsed K-PKE encryption key.
+// It caches the decoded t̂ and the matrix Â used by K-PKE.Encrypt.
+type EncryptionKey struct {
+	k  int
+	t_ [maxK]polynomial
+	A_ [maxK][maxK]polynomial
+	ro [32]byte
 }
 
-func KPKEKeyGen(d []byte, k, eta1 int) ([]byte, []byte) {
+// DecryptionKey is a parsed K-PKE decryption key.
+type DecryptionKey struct {
+	k  int
+	s_ [maxK]polynomial
+}
+
+func KPKEKeyGen(ekPKE, dkPKE, d []byte, k, eta1 int) {
+	var ek EncryptionKey
+	var dk DecryptionKey
+	kpkeKeyGen(&ek, &dk, d, k, eta1)
+	ek.encode(ekPKE)
+	dk.encode(dkPKE)
+	dk.Destroy()
+}
+
+func kpkeKeyGen(ek *EncryptionKey, dk *DecryptionKey, d []byte, k, eta1 int) {
 	ro, sigma := G(d, []byte{byte(k)})
 
-	A_ := make([][]polynomial, k)
-	for i := range byte(k) {
-		A_[i] = make([]polynomial, k)
-		for j := range byte(k) {
-			A_[i][j] = SampleNTT(ro, j, i)
-		}
-	}
+	ek.k, ek.ro = k, ro
+	ek.sampleMatrix()
 
 	var N byte
-	s_ := make([]polynomial, k)
+	dk.k = k
 	for i := range k {
-		s_[i] = NTT(SamplePolyCBD(PRF(sigma, N, eta1)))
+		dk.s_[i].samplePolyCBDPRF(sigma[:], N, eta1)
+		dk.s_[i].ntt()
 		N++
 	}
-	e_ := make([]polynomial, k)
+	var e_ polynomial
 	for i := range k {
-		e_[i] = NTT(SamplePolyCBD(PRF(sigma, N, eta1)))
+		// t̂[i] = Σⱼ Â[i][j] × ŝ[j] + ê[i]
+		t_ := &ek.t_[i]
+		*t_ = polynomial{}
+		for j := range k {
+			t_.multiplyAcc(&ek.A_[i][j], &dk.s_[j])
+		}
+		t_.reduceAcc()
+		e_.samplePolyCBDPRF(sigma[:], N, eta1)
+		e_.ntt()
+		t_.add(&e_)
 		N++
 	}
-
-	t_ := vectorAdd(matrixMultiplyNTTs(A_, s_), e_)
-
-	ekPKE := make([]byte, 0, k*(32*12)+32)
-	for i := range k {
-		ekPKE = append(ekPKE, ByteEncodeQ(t_[i])...)
+	clear(sigma[:])
+	e_ = polynomial{}
+}
+
+// sampleMatrix generates Â from ρ, four entries at a time.
+func (ek *EncryptionKey) sampleMatrix() {
+	var a [4]*polynomial
+	var ji [4][2]byte
+	n := 0
+	for i := range ek.k {
+		for j := range ek.k {
+			a[n], ji[n] = &ek.A_[i][j], [2]byte{byte(j), byte(i)}
+			if n++; n == 4 {
+				sampleNTTx4(&a, ek.ro[:], ji)
+				a, n = [4]*polynomial{}, 0
+			}
+		}
 	}
-	ekPKE = append(ekPKE, ro...)
-
-	dkPKE := make([]byte, 0, k*32*12)
-	for i := range k {
-		dkPKE = append(dkPKE, ByteEncodeQ(s_[i])...)
+	if n > 0 {
+		sampleNTTx4(&a, ek.ro[:], ji)
 	}
-	return ekPKE, dkPKE
 }
 
-func matrixMultiplyNTTs(A_ [][]polynomial, s_ []polynomial) []polynomial {
-	k := len(A_)
-	r_ := make([]polynomial, k)
+// decode parses the 384k+32-byte encryption key ekPKE.
+func (ek *EncryptionKey) decode(ekPKE []byte, k int) {
+	ek.k = k
 	for i := range k {
-		r_[i] = dotProductNTTs(A_[i], s_)
+		ek.t_[i].byteDecodeQ(ekPKE[32*12*i : 32*12*(i+1)])
 	}
-	return r_
+	copy(ek.ro[:], ekPKE[384*k:384*k+32])
+	ek.sampleMatrix()
 }
 
-func dotProductNTTs(t_ []polynomial, s_ []polynomial) polynomial {
-	k := len(t_)
-	var r_ polynomial
-	for i := range k {
-		r_ = add(r_, MultiplyNTTs(t_[i], s_[i]))
-	}
-	return r_
+func NewEncryptionKey(ekPKE []byte, k int) *EncryptionKey {
+	ek := new(EncryptionKey)
+	ek.decode(ekPKE, k)
+	return ek
 }
 
-func transpose(a [][]polynomial) [][]polynomial {
-	k := len(a)
-	r := make([][]polynomial, k)
-	for i := range k {
-		r[i] = make([]polynomial, k)
+// encode writes the 384k+32-byte encoding of the key to ekPKE.
+func (ek *EncryptionKey) encode(ekPKE []byte) {
+	for i := range ek.k {
+		ek.t_[i].byteEncodeQ(ekPKE[32*12*i : 32*12*(i+1)])
 	}
-	for i := range k {
-		for j := range k {
-			r[j][i] = a[i][j]
-		}
-	}
-	return r
+	copy(ekPKE[384*ek.k:], ek.ro[:])
 }
 
-func KPKEEncrypt(ekPKE []byte, m, r []byte, k, eta1, eta2, du, dv int) []byte {
-	t_ := make([]polynomial, k)
-	for i := range k {
-		t_[i] = ByteDecodeQ(ekPKE[32*12*i : 32*12*(i+1)])
-	}
-	ro := ekPKE[384*k : 384*k+32]
+func (ek *EncryptionKey) Bytes() []byte {
+	ekPKE := make([]byte, 384*ek.k+32)
+	ek.encode(ekPKE)
+	return ekPKE
+}
 
-	A_ := make([][]polynomial, k)
-	for i := range byte(k) {
-		A_[i] = make([]polynomial, k)
-		for j := range byte(k) {
-			A_[i][j] = SampleNTT(ro, j, i)
-		}
-	}
+func KPKEEncrypt(c, ekPKE, m, r []byte, k, eta1, eta2, du, dv int) {
+	var ek EncryptionKey
+	ek.decode(ekPKE, k)
+	ek.Encrypt(c, m, r, eta1, eta2, du, dv)
+}
+
+// Encrypt writes the 32(du·k+dv)-byte ciphertext of the message m encrypted with randomness r to c.
+func (ek *EncryptionKey) Encrypt(c, m, r []byte, eta1, eta2, du, dv int) {
+	k := ek.k
 
 	var N byte
-	y_ := make([]polynomial, k)
+	var y_ [maxK]polynomial
 	for i := range k {
-		y_[i] = NTT(SamplePolyCBD(PRF(r, N, eta1)))
+		y_[i].samplePolyCBDPRF(r, N, eta1)
+		y_[i].ntt()
 		N++
 	}
-	e1 := make([]polynomial, k)
+
+	var u, e polynomial
 	for i := range k {
-		e1[i] = SamplePolyCBD(PRF(r, N, eta2))
+		// u[i] = NTT⁻¹(Σⱼ Âᵀ[i][j] × ŷ[j]) + e₁[i]
+		u = polynomial{}
+		for j := range k {
+			u.multiplyAcc(&ek.A_[j][i], &y_[j])
+		}
+		u.reduceAcc()
+		u.nttInv()
+		e.samplePolyCBDPRF(r, N, eta2)
+		u.add(&e)
 		N++
+		u.compressEncode(c[32*du*i:32*du*(i+1)], du)
 	}
-	e2 := SamplePolyCBD(PRF(r, N, eta2))
 
-	u := vectorAdd(vectorNTTinv(matrixMultiplyNTTs(transpose(A_), y_)), e1)
-	mu := Decompress(ByteDecode(m, 1), 1)
-	v := add(add(NTTinv(dotProductNTTs(t_, y_)), e2), mu)
+	// v = NTT⁻¹(t̂ᵀ × ŷ) + e₂ + μ
+	var v polynomial
+	for j := range k {
+		v.multiplyAcc(&ek.t_[j], &y_[j])
+	}
+	v.reduceAcc()
+	v.nttInv()
+	e.samplePolyCBDPRF(r, N, eta2)
+	v.add(&e)
+	var mu polynomial
+	mu.decodeDecompress(m, 1)
+	v.add(&mu)
+	v.compressEncode(c[32*du*k:32*(du*k+dv)], dv)
+	clear(y_[:])
+	e, v, mu = polynomial{}, polynomial{}, polynomial{}
+}
 
-	c1 := make([]byte, 0, 32*(du*k+dv))
+// decode parses the 384k-byte decryption key dkPKE.
+func (dk *DecryptionKey) decode(dkPKE []byte, k int) {
+	dk.k = k
 	for i := range k {
-		c1 = append(c1, ByteEncode(Compress(u[i], du), du)...)
+		dk.s_[i].byteDecodeQ(dkPKE[32*12*i : 32*12*(i+1)])
 	}
-	c2 := ByteEncode(Compress(v, dv), dv)
-	c := append(c1, c2...)
+}
 
-	return c
+func NewDecryptionKey(dkPKE []byte, k int) *DecryptionKey {
+	dk := new(DecryptionKey)
+	dk.decode(dkPKE, k)
+	return dk
 }
 
-func KPKEDecrypt(dkPKE []byte, c []byte, k, du, dv int) []byte {
+// encode writes the 384k-byte encoding of the key to dkPKE.
+func (dk *DecryptionKey) encode(dkPKE []byte) {
+	for i := range dk.k {
+		dk.s_[i].byteEncodeQ(dkPKE[32*12*i : 32*12*(i+1)])
+	}
+}
+
+func (dk *DecryptionKey) Bytes() []byte {
+	dkPKE := make([]byte, 384*dk.k)
+	dk.encode(dkPKE)
+	return dkPKE
+}
+
+func KPKEDecrypt(m, dkPKE, c []byte, k, du, dv int) {
+	var dk DecryptionKey
+	dk.decode(dkPKE, k)
+	dk.Decrypt(m, c, du, dv)
+	dk.Destroy()
+}
+
+// Decrypt writes the 32-byte message decrypted from the ciphertext c to m.
+func (dk *DecryptionKey) Decrypt(m, c []byte, du, dv int) {
+	k := dk.k
 	c1 := c[0 : 32*du*k]
 	c2 := c[32*du*k : 32*(du*k+dv)]
-	u_ := make([]polynomial, k)
-	for i := range k {
-		u_[i] = NTT(Decompress(ByteDecode(c1[32*du*i:32*du*(i+1)], du), du))
-	}
-	v := Decompress(ByteDecode(c2, dv), dv)
 
-	s_ := make([]polynomial, k)
+	// w = v − NTT⁻¹(ŝᵀ × NTT(u))
+	var u, w polynomial
 	for i := range k {
-		s_[i] = ByteDecodeQ(dkPKE[32*12*i : 32*12*(i+1)])
+		u.decodeDecompress(c1[32*du*i:32*du*(i+1)], du)
+		u.ntt()
+		w.multiplyAcc(&dk.s_[i], &u)
 	}
-	w := sub(v, NTTinv(dotProductNTTs(s_, u_)))
-	m := ByteEncode(Compress(w, 1), 1)
+	w.reduceAcc()
+	w.nttInv()
+	var v polynomial
+	v.decodeDecompress(c2, dv)
+	v.sub(&w)
+	v.compressEncode(m, 1)
+	w, v = polynomial{}, polynomial{}
+}
 
-	return m
+// Destroy zeroes ŝ.
+func (dk *DecryptionKey) Destroy() {
+	clear(dk.s_[:])
 }
 
-func KeyGen_internal(d, z []byte, k, eta1 int) ([]byte, []byte) {
-	ekPKE, dkPKE := KPKEKeyGen(d, k, eta1)
-	ek := ekPKE
-	dk := make([]byte, 0, len(dkPKE)+len(ek)+32+len(z))
-	dk = append(dk, dkPKE...)
-	dk = append(dk, ek...)
-	dk = append(dk, H(ek)...)
-	dk = append(dk, z...)
-	return ek, dk
+// EncapsulationKey is a parsed ML-KEM encapsulation key.
+// It caches H(ek) and the parsed K-PKE encryption key.
+type EncapsulationKey struct {
+	EncryptionKey
+	h [32]byte
 }
 
-func Encaps_internal(ek, m []byte, k, eta1, eta2, du, dv int) ([]byte, []byte) {
-	K, r := G(m, H(ek))
-	c := KPKEEncrypt(ek, m, r, k, eta1, eta2, du, dv)
-	return K, c
+// DecapsulationKey is a parsed ML-KEM decapsulation key.
+// It caches the parsed K-PKE decryption key and the embedded encapsulation key.
+type DecapsulationKey struct {
+	DecryptionKey
+	ek EncapsulationKey
+	z  [32]byte
 }
 
-func Decaps_internal(dk, c []byte, k, eta1, eta2, du, dv int) []byte {
-	dkPKE := dk[0 : 384*k]
-	ekPKE := dk[384*k : 768*k+32]
-	h := dk[768*k+32 : 768*k+64]
-	z := dk[768*k+64 : 768*k+96]
-	m := KPKEDecrypt(dkPKE, c, k, du, dv)
-	K, r := G(m, h)
-	K_ := J(z, c)
-	c_ := KPKEEncrypt(ekPKE, m, r, k, eta1, eta2, du, dv)
-	if !bytes.Equal(c, c_) {
-		copy(K, K_)
-	}
-	return K
+// Generate runs ML-KEM.KeyGen_internal and stores the key in dk.
+func (dk *DecapsulationKey) Generate(d, z []byte, k, eta1 int) {
+	kpkeKeyGen(&dk.ek.EncryptionKey, &dk.DecryptionKey, d, k, eta1)
+	var ek [384*maxK + 32]byte
+	dk.ek.encode(ek[:384*k+32])
+	dk.ek.h = H(ek[:384*k+32])
+	copy(dk.z[:], z)
+}
+
+// KeyGen runs ML-KEM.KeyGen_internal and returns the parsed decapsulation key.
+func KeyGen(d, z []byte, k, eta1 int) *DecapsulationKey {
+	dk := new(DecapsulationKey)
+	dk.Generate(d, z, k, eta1)
+	return dk
+}
+
+// KeyGen_internal writes the 384k+32-byte encapsulation key to ek
+// and the 768k+96-byte decapsulation key to dk.
+func KeyGen_internal(ek, dk, d, z []byte, k, eta1 int) {
+	var key DecapsulationKey
+	key.Generate(d, z, k, eta1)
+	key.ek.encode(ek)
+	key.encode(dk)
+	key.Destroy()
+}
+
+// decode parses the 384k+32-byte encapsulation key ek.
+func (ek *EncapsulationKey) decode(b []byte, k int) {
+	ek.EncryptionKey.decode(b, k)
+	ek.h = H(b)
+}
+
+func NewEncapsulationKey(ek []byte, k int) *EncapsulationKey {
+	key := new(EncapsulationKey)
+	key.decode(ek, k)
+	return key
+}
+
+func Encaps_internal(K, c, ek, m []byte, k, eta1, eta2, du, dv int) {
+	var key EncapsulationKey
+	key.decode(ek, k)
+	key.Encaps(K, c, m, eta1, eta2, du, dv)
+}
+
+// Encaps writes the 32-byte shared key to K and the ciphertext to c.
+func (ek *EncapsulationKey) Encaps(K, c, m []byte, eta1, eta2, du, dv int) {
+	K_, r := G(m, ek.h[:])
+	ek.Encrypt(c, m, r[:], eta1, eta2, du, dv)
+	copy(K, K_[:])
+	clear(K_[:])
+	clear(r[:])
+}
+
+// Decode parses the 768k+96-byte decapsulation key b into dk.
+// The hash h is taken from b and not recomputed.
+func (dk *DecapsulationKey) Decode(b []byte, k int) {
+	dk.DecryptionKey.decode(b[0:384*k], k)
+	dk.ek.EncryptionKey.decode(b[384*k:768*k+32], k)
+	copy(dk.ek.h[:], b[768*k+32:768*k+64])
+	copy(dk.z[:], b[768*k+64:768*k+96])
+}
+
+func NewDecapsulationKey(dk []byte, k int) *DecapsulationKey {
+	key := new(DecapsulationKey)
+	key.Decode(dk, k)
+	return key
+}
+
+// encode writes the 768k+96-byte encoding of the key to b.
+func (dk *DecapsulationKey) encode(b []byte) {
+	k := dk.k
+	dk.DecryptionKey.encode(b[0 : 384*k])
+	dk.ek.encode(b[384*k : 768*k+32])
+	copy(b[768*k+32:], dk.ek.h[:])
+	copy(b[768*k+64:], dk.z[:])
+}
+
+func (dk *DecapsulationKey) Bytes() []byte {
+	b := make([]byte, 768*dk.k+96)
+	dk.encode(b)
+	return b
+}
+
+func (dk *DecapsulationKey) EncapsulationKey() *EncapsulationKey {
+	return &dk.ek
+}
+
+// Destroy zeroes ŝ and z. The embedded encapsulation key is public and left intact.
+func (dk *DecapsulationKey) Destroy() {
+	dk.DecryptionKey.Destroy()
+	clear(dk.z[:])
+}
+
+func Decaps_internal(K, dk, c []byte, k, eta1, eta2, du, dv int) {
+	var key DecapsulationKey
+	key.Decode(dk, k)
+	key.Decaps(K, c, eta1, eta2, du, dv)
+	key.Destroy()
+}
+
+// Decaps writes the 32-byte shared key decapsulated from the ciphertext c to K.
+func (dk *DecapsulationKey) Decaps(K, c []byte, eta1, eta2, du, dv int) {
+	var m [32]byte
+	dk.Decrypt(m[:], c, du, dv)
+	K_, r := G(m[:], dk.ek.h[:])
+	Kbar := J(dk.z[:], c)
+	var c_ [32 * (11*maxK + 5)]byte
+	dk.ek.Encrypt(c_[:len(c)], m[:], r[:], eta1, eta2, du, dv)
+	eq := subtle.ConstantTimeCompare(c, c_[:len(c)])
+	subtle.ConstantTimeCopy(1-eq, K_[:], Kbar[:])
+	copy(K, K_[:])
+	clear(m[:])
+	clear(K_[:])
+	clear(r[:])
+	clear(Kbar[:])
+	clear(c_[:])
 }
 
 func ByteEncodeQ(f polynomial) []byte {
 	b := make([]byte, 384)
-	for i, a := range f {
-		for j := range 12 {
-			setBit(b, i*12+j, int((a>>j)&1))
-		}
-	}
+	f.byteEncodeQ(b)
 	return b
 }
 
 func ByteDecodeQ(b []byte) polynomial {
 	var f polynomial
-	for i := range f {
-		var a uintq
-		for j := range 12 {
-			a |= uintq(getBit(b, i*12+j) << j)
-		}
-		f[i] = a
-	}
+	f.byteDecodeQ(b)
 	return f
 }
 
+// CheckModulus reports whether ByteEncode₁₂(ByteDecode₁₂(b)) == b,
+// i.e. every 12-bit coefficient encoded in b is less than q.
+// b may hold ŝ, so the temporaries are zeroed before returning.
+func CheckModulus(b []byte) bool {
+	var f polynomial
+	var e [384]byte
+	ok := true
+	for i := 0; i < len(b) && ok; i += 384 {
+		f.byteDecodeQ(b[i : i+384])
+		f.byteEncodeQ(e[:])
+		ok = subtle.ConstantTimeCompare(e[:], b[i:i+384]) == 1
+	}
+	f, e = polynomial{}, [384]byte{}
+	return ok
+}
+
 func ByteEncode(f [256]uint, d int) []byte {
-	b := make([]byte, 32*d)
-	for i, a := range f {
-		for j := range d {
-			setBit(b, i*d+j, int((a>>j)&1))
-		}
+	var c compressed
+	for i := range f {
+		c[i] = uint16(f[i])
 	}
+	b := make([]byte, 32*d)
+	byteEncode(b, c[:], d)
 	return b
 }
 
 func ByteDecode(b []byte, d int) [256]uint {
+	var c compressed
+	byteDecode(c[:], b, d)
 	var f [256]uint
 	for i := range f {
-		var a uint
-		for j := range d {
-			a |= uint(getBit(b, i*d+j) << j)
-		}
-		f[i] = a
+		f[i] = uint(c[i])
 	}
 	return f
 }
 
 func Decompress(b [256]uint, d int) polynomial {
 	var f polynomial
-	pow2d := uintq2(1 << d)
 	for i := range f {
-		f[i] = uintq((uintq2(b[i])*q + pow2d/2) / pow2d)
+		f[i] = decompress(uint16(b[i]), d)
 	}
 	return f
 }
 
 func Compress(f polynomial, d int) [256]uint {
+	var c compressed
+	polyCompress(&c, &f, d)
 	var b [256]uint
-	pow2d := uintq2(1 << d)
-	for i := range f {
-		b[i] = uint(((uintq2(f[i])*pow2d + q/2) / q) % pow2d)
+	for i := range b {
+		b[i] = uint(c[i])
 	}
 	return b
 }
 
+func polyCompressGeneric(b *compressed, f *polynomial, d int) {
+	for i := range f {
+		b[i] = uint16(compress(f[i], d))
+	}
+}
+
+// compress computes ⌈(2ᵈ/q)·x⌋ mod 2ᵈ = ⌊(x·2ᵈ + ⌊q/2⌋)/q⌋ mod 2ᵈ for x < q and d ≤ 11.
+//
+// The division by q is replaced by multiplication with m = ⌈2³⁵/q⌉ and a shift,
+// which is exact for dividends below 2²³ and does not depend on the timing of hardware division
+// (see KyberSlash).
+func compress(x int16, d int) uint {
+	const m = (1<<35 + q - 1) / q
+	y := uint64(x)<<d + q/2 // < 2²³
+	return uint(y*m>>35) & (1<<d - 1)
+}
+
+// decompress computes ⌈(q/2ᵈ)·y⌋ for y < 2ᵈ.
+func decompress(y uint16, d int) int16 {
+	return int16((uintq2(y)*q + 1<<d>>1) >> d)
+}
+
 func getBit(b []byte, i int) int {
 	return (int(b[i/8]) >> (i % 8)) & 1
 }
@@ -405,41 +759,3 @@ func getBit(b []byte, i int) int {
 func setBit(b []byte, i int, v int) {
 	b[i/8] |= byte((v << (i % 8)))
 }
-
-var zetaBitRev7 = [128]uintq2{
-	1, 1729, 2580, 3289, 2642, 630, 1897, 848,
-	1062, 1919, 193, 797, 2786, 3260, 569, 1746,
-	296, 2447, 1339, 1476, 3046, 56, 2240, 1333,
-	1426, 2094, 535, 2882, 2393, 2879, 1974, 821,
-	289, 331, 3253, 1756, 1197, 2304, 2277, 2055,
-	650, 1977, 2513, 632, 2865, 33, 1320, 1915,
-	2319, 1435, 807, 452, 1438, 2868, 1534, 2402,
-	2647, 2617, 1481, 648, 2474, 3110, 1227, 910,
-	17, 2761, 583, 2649, 1637, 723, 2288, 1100,
-	1409, 2662, 3281, 233, 756, 2156, 3015, 3050,
-	1703, 1651, 2789, 1789, 1847, 952, 1461, 2687,
-	939, 2308, 2437, 2388, 733, 2337, 268, 641,
-	1584, 2298, 2037, 3220, 375, 2549, 2090, 1645,
-	1063, 319, 2773, 757, 2099, 561, 2466, 2594,
-	2804, 1092, 403, 1026, 1143, 2150, 2775, 886,
-	1722, 1212, 1874, 1029, 2110, 2935, 885, 2154,
-}
-
-var zeta2BitRev7 = [128]intq2{
-	17, -17, 2761, -2761, 583, -583, 2649, -2649,
-	1637, -1637, 723, -723, 2288, -2288, 1100, -1100,
-	1409, -1409, 2662, -2662, 3281, -3281, 233, -233,
-	756, -756, 2156, -2156, 3015, -3015, 3050, -3050,
-	1703, -1703, 1651, -1651, 2789, -2789, 1789, -1789,
-	1847, -1847, 952, -952, 1461, -1461, 2687, -2687,
-	939, -939, 2308, -2308, 2437, -2437, 2388, -2388,
-	733, -733, 2337, -2337, 268, -268, 641, -641,
-	1584, -1584, 2298, -2298, 2037, -2037, 3220, -3220,
-	375, -375, 2549, -2549, 2090, -2090, 1645, -1645,
-	1063, -1063, 319, -319, 2773, -2773, 757, -757,
-	2099, -2099, 561, -561, 2466, -2466, 2594, -2594,
-	2804, -2804, 1092, -1092, 403, -403, 1026, -1026,
-	1143, -1143, 2150, -2150, 2775, -2775, 886, -886,
-	1722, -1722, 1212, -1212, 1874, -1874, 1029, -1029,
-	2110, -2110, 2935, -2935, 885, -885, 2154, -2154,
-}
@@ -0,0 +1,178 @@
+//go:build !purego
+
+package internal
+
+var useAVX2 = hasAVX2()
+
+// hasAVX2 reports whether the CPU supports AVX2 and the operating system saves the YMM registers.
+func hasAVX2() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	const osxsave, avx = 1 << 27, 1 << 28
+	if _, _, ecx, _ := cpuid(1, 0); ecx&osxsave == 0 || ecx&avx == 0 {
+		return false
+	}
+	if eax, _ := xgetbv(); eax&0b110 != 0b110 { // XMM and YMM state
+		return false
+	}
+	const avx2 = 1 << 5
+	_, ebx, _, _ := cpuid(7, 0)
+	return ebx&avx2 != 0
+}
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
+
+//go:noescape
+func nttAVX2(f *polynomial)
+
+//go:noescape
+func nttInvAVX2(f *polynomial)
+
+//go:noescape
+func multiplyAccAVX2(h, a, b *polynomial)
+
+//go:noescape
+func reduceAccAVX2(h *polynomial)
+
+//go:noescape
+func samplePolyCBD2AVX2(f *polynomial, b *[64 * 2]byte)
+
+//go:noescape
+func samplePolyCBD3AVX2(f *polynomial, b *[64 * 3]byte)
+
+//go:noescape
+func compressAVX2(b *compressed, f *polynomial, d int)
+
+//go:noescape
+func keccakF1600x4AVX2(s *keccak4)
+
+func (f *polynomial) ntt() {
+	if useAVX2 {
+		nttAVX2(f)
+		return
+	}
+	f.nttGeneric()
+}
+
+func (f *polynomial) nttInv() {
+	if useAVX2 {
+		nttInvAVX2(f)
+		return
+	}
+	f.nttInvGeneric()
+}
+
+func (h *polynomial) multiplyAcc(f, g *polynomial) {
+	if useAVX2 {
+		multiplyAccAVX2(h, f, g)
+		return
+	}
+	h.multiplyAccGeneric(f, g)
+}
+
+func (h *polynomial) reduceAcc() {
+	if useAVX2 {
+		reduceAccAVX2(h)
+		return
+	}
+	h.reduceAccGeneric()
+}
+
+func (f *polynomial) samplePolyCBD(b []byte) {
+	if useAVX2 {
+		switch len(b) {
+		case 64 * 2:
+			samplePolyCBD2AVX2(f, (*[64 * 2]byte)(b))
+			return
+		case 64 * 3:
+			samplePolyCBD3AVX2(f, (*[64 * 3]byte)(b))
+			return
+		}
+	}
+	f.samplePolyCBDBitsliced(b)
+}
+
+func (f *polynomial) samplePolyCBDPRF(s []byte, b byte, eta int) {
+	if useAVX2 {
+		var prf [64 * 3]byte
+		PRF(prf[:64*eta], s, b)
+		f.samplePolyCBD(prf[:64*eta])
+		clear(prf[:])
+		return
+	}
+	f.samplePolyCBDPRFGeneric(s, b, eta)
+}
+
+func polyCompress(b *compressed, f *polynomial, d int) {
+	if useAVX2 {
+		compressAVX2(b, f, d)
+		return
+	}
+	polyCompressGeneric(b, f, d)
+}
+
+func (f *polynomial) compressEncode(b []byte, d int) {
+	if useAVX2 {
+		var c compressed
+		compressAVX2(&c, f, d)
+		byteEncode(b, c[:], d)
+		return
+	}
+	f.compressEncodeGeneric(b, d)
+}
+
+func (s *keccak4) permute() {
+	if useAVX2 {
+		keccakF1600x4AVX2(s)
+		return
+	}
+	s.permuteGeneric()
+}
+
+// Twiddle factors for the assembly.
+//
+// The layers with len ≥ 16 broadcast zetasMont[i] and zetasMontQinv[i].
+// The layers with len = 8, 4 and 2 process 32 coefficients at a time
+// and permute them so that the first operands of the butterflies are in one vector
+// and the second operands in another; nttZetasAVX2 and nttInvZetasAVX2 hold the factors in that order.
+var (
+	zetasMontQinv   [128]int16         // zetasMont[i]·q⁻¹ mod 2¹⁶
+	nttZetasAVX2    [3][8][2][16]int16 // len = 8, 4, 2: [ζ, ζ·q⁻¹] for each 32-coefficient chunk
+	nttInvZetasAVX2 [3][8][2][16]int16 // len = 2, 4, 8
+	gammasAVX2      [256]int16         // gammasMont[i] at odd indices
+)
+
+// avx2Lanes[l][n] is the offset within a 32-coefficient chunk of the first butterfly operand
+// in lane n for len = 8 >> l, see the permutations in mlkem_amd64.s.
+var avx2Lanes = [3][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 16, 17, 18, 19, 20, 21, 22, 23},
+	{0, 1, 2, 3, 16, 17, 18, 19, 8, 9, 10, 11, 24, 25, 26, 27},
+	{0, 1, 4, 5, 16, 17, 20, 21, 8, 9, 12, 13, 24, 25, 28, 29},
+}
+
+func init() {
+	for i, z := range zetasMont {
+		zetasMontQinv[i] = z * qInv
+	}
+	for l, lanes := range avx2Lanes {
+		len := 8 >> l
+		for m := range 8 {
+			for n, off := range lanes {
+				block := (32*m + off) / (2 * len)
+				z := zetasMont[128/len+block]
+				nttZetasAVX2[l][m][0][n] = z
+				nttZetasAVX2[l][m][1][n] = z * qInv
+				z = zetasMont[256/len-1-block]
+				nttInvZetasAVX2[2-l][m][0][n] = z
+				nttInvZetasAVX2[2-l][m][1][n] = z * qInv
+			}
+		}
+	}
+	for i, g := range gammasMont {
+		gammasAVX2[2*i+1] = g
+	}
+}
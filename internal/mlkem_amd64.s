@@ -0,0 +1,526 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// The vector arithmetic mirrors field.go and works on 16 coefficients at a time.
+// Y15 holds q in every 16-bit lane.
+
+// FQMUL sets t = b·ζ·R⁻¹ in (-q, q) given z = ζ and zq = ζ·q⁻¹ mod 2¹⁶.
+#define FQMUL(b, z, zq, t, tmp) \
+	VPMULLW zq, b, tmp;    \
+	VPMULHW z, b, t;       \
+	VPMULHW Y15, tmp, tmp; \
+	VPSUBW  tmp, t, t
+
+// BARRETT reduces a to the centered range, Y13 and Y12 hold 20159 and 512.
+#define BARRETT(a, t) \
+	VPMULHW Y13, a, t; \
+	VPADDW  Y12, t, t; \
+	VPSRAW  $10, t, t; \
+	VPMULLW Y15, t, t; \
+	VPSUBW  t, a, a
+
+// CONDADDQ adds q to the negative lanes of a.
+#define CONDADDQ(a, t) \
+	VPSRAW $15, a, t; \
+	VPAND  Y15, t, t; \
+	VPADDW t, a, a
+
+// BUTTERFLY computes a, b = a + ζ·b, a − ζ·b.
+#define BUTTERFLY(a, b, z, zq, t0, t1) \
+	FQMUL(b, z, zq, t0, t1); \
+	VPSUBW t0, a, b;         \
+	VPADDW t0, a, a
+
+// INVBUTTERFLY computes a, b = a + b, ζ·(b − a).
+#define INVBUTTERFLY(a, b, z, zq, t0, t1) \
+	VPSUBW a, b, t0; \
+	VPADDW b, a, a;  \
+	BARRETT(a, t1);  \
+	FQMUL(t0, z, zq, b, t1)
+
+#define BROADCASTW(imm, x, y) \
+	MOVL         imm, AX; \
+	VMOVD        AX, x;   \
+	VPBROADCASTW x, y
+
+#define BROADCASTD(imm, x, y) \
+	MOVL         imm, AX; \
+	VMOVD        AX, x;   \
+	VPBROADCASTD x, y
+
+#define BROADCASTB(imm, x, y) \
+	MOVL         imm, AX; \
+	VMOVD        AX, x;   \
+	VPBROADCASTB x, y
+
+// Permutations of two vectors x and y holding 32 consecutive coefficients
+// into vectors a and b of the first and second butterfly operands, see avx2Lanes.
+// Applying the same permutation to a and b restores the original order.
+
+#define PERM8(x, y, a, b) \
+	VPERM2I128 $0x20, y, x, a; \
+	VPERM2I128 $0x31, y, x, b
+
+#define PERM4(x, y, a, b) \
+	VPUNPCKLQDQ y, x, a; \
+	VPUNPCKHQDQ y, x, b
+
+#define PERM2(x, y, a, b) \
+	VPSHUFD     $0xd8, x, x; \
+	VPSHUFD     $0xd8, y, y; \
+	VPUNPCKLQDQ y, x, a;     \
+	VPUNPCKHQDQ y, x, b
+
+#define UNPERM2(a, b, x, y) \
+	VPUNPCKLQDQ b, a, x;     \
+	VPUNPCKHQDQ b, a, y;     \
+	VPSHUFD     $0xd8, x, x; \
+	VPSHUFD     $0xd8, y, y
+
+// SHORTLAYER runs one layer with len = 8, 4 or 2 over the 32 coefficients at DI+DX
+// using the twiddle factors at SI.
+#define SHORTLAYER(PERM, UNPERM, BF) \
+	VMOVDQU (DI)(DX*1), Y0;   \
+	VMOVDQU 32(DI)(DX*1), Y1; \
+	PERM(Y0, Y1, Y3, Y4);     \
+	VMOVDQU (SI), Y1;         \
+	VMOVDQU 32(SI), Y2;       \
+	BF(Y3, Y4, Y1, Y2, Y5, Y6); \
+	UNPERM(Y3, Y4, Y0, Y1)
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
+
+// func nttAVX2(f *polynomial)
+TEXT ·nttAVX2(SB), NOSPLIT, $0-8
+	MOVQ f+0(FP), DI
+	BROADCASTW($3329, X15, Y15)
+	BROADCASTW($20159, X13, Y13)
+	BROADCASTW($512, X12, Y12)
+
+	// Layers with len = 128, 64, 32 and 16; BX is len in bytes, CX is start and DX is j.
+	LEAQ ·zetasMont+2(SB), SI
+	LEAQ ·zetasMontQinv+2(SB), R8
+	MOVQ $256, BX
+
+nttLayer:
+	XORQ CX, CX
+
+nttBlock:
+	VPBROADCASTW (SI), Y1
+	VPBROADCASTW (R8), Y2
+	ADDQ         $2, SI
+	ADDQ         $2, R8
+	MOVQ         CX, DX
+	LEAQ         (CX)(BX*1), R10
+
+nttButterfly:
+	LEAQ    (DX)(BX*1), R11
+	VMOVDQU (DI)(DX*1), Y3
+	VMOVDQU (DI)(R11*1), Y4
+	BUTTERFLY(Y3, Y4, Y1, Y2, Y5, Y6)
+	VMOVDQU Y3, (DI)(DX*1)
+	VMOVDQU Y4, (DI)(R11*1)
+	ADDQ    $32, DX
+	CMPQ    DX, R10
+	JB      nttButterfly
+	LEAQ    (CX)(BX*2), CX
+	CMPQ    CX, $512
+	JB      nttBlock
+	SHRQ    $1, BX
+	CMPQ    BX, $32
+	JAE     nttLayer
+
+	// Layers with len = 8, 4 and 2.
+	LEAQ ·nttZetasAVX2(SB), SI
+	XORQ DX, DX
+
+ntt8:
+	SHORTLAYER(PERM8, PERM8, BUTTERFLY)
+	VMOVDQU Y0, (DI)(DX*1)
+	VMOVDQU Y1, 32(DI)(DX*1)
+	ADDQ    $64, SI
+	ADDQ    $64, DX
+	CMPQ    DX, $512
+	JB      ntt8
+
+	XORQ DX, DX
+
+ntt4:
+	SHORTLAYER(PERM4, PERM4, BUTTERFLY)
+	VMOVDQU Y0, (DI)(DX*1)
+	VMOVDQU Y1, 32(DI)(DX*1)
+	ADDQ    $64, SI
+	ADDQ    $64, DX
+	CMPQ    DX, $512
+	JB      ntt4
+
+	XORQ DX, DX
+
+ntt2:
+	SHORTLAYER(PERM2, UNPERM2, BUTTERFLY)
+
+	// Reduce to the canonical range.
+	BARRETT(Y0, Y5)
+	CONDADDQ(Y0, Y5)
+	BARRETT(Y1, Y5)
+	CONDADDQ(Y1, Y5)
+	VMOVDQU Y0, (DI)(DX*1)
+	VMOVDQU Y1, 32(DI)(DX*1)
+	ADDQ    $64, SI
+	ADDQ    $64, DX
+	CMPQ    DX, $512
+	JB      ntt2
+
+	VZEROUPPER
+	RET
+
+// func nttInvAVX2(f *polynomial)
+TEXT ·nttInvAVX2(SB), NOSPLIT, $0-8
+	MOVQ f+0(FP), DI
+	BROADCASTW($3329, X15, Y15)
+	BROADCASTW($20159, X13, Y13)
+	BROADCASTW($512, X12, Y12)
+
+	// Layers with len = 2, 4 and 8.
+	LEAQ ·nttInvZetasAVX2(SB), SI
+	XORQ DX, DX
+
+inv2:
+	SHORTLAYER(PERM2, UNPERM2, INVBUTTERFLY)
+	VMOVDQU Y0, (DI)(DX*1)
+	VMOVDQU Y1, 32(DI)(DX*1)
+	ADDQ    $64, SI
+	ADDQ    $64, DX
+	CMPQ    DX, $512
+	JB      inv2
+
+	XORQ DX, DX
+
+inv4:
+	SHORTLAYER(PERM4, PERM4, INVBUTTERFLY)
+	VMOVDQU Y0, (DI)(DX*1)
+	VMOVDQU Y1, 32(DI)(DX*1)
+	ADDQ    $64, SI
+	ADDQ    $64, DX
+	CMPQ    DX, $512
+	JB      inv4
+
+	XORQ DX, DX
+
+inv8:
+	SHORTLAYER(PERM8, PERM8, INVBUTTERFLY)
+	VMOVDQU Y0, (DI)(DX*1)
+	VMOVDQU Y1, 32(DI)(DX*1)
+	ADDQ    $64, SI
+	ADDQ    $64, DX
+	CMPQ    DX, $512
+	JB      inv8
+
+	// Layers with len = 16, 32, 64 and 128; BX is len in bytes, CX is start and DX is j.
+	LEAQ ·zetasMont+30(SB), SI
+	LEAQ ·zetasMontQinv+30(SB), R8
+	MOVQ $32, BX
+
+invLayer:
+	XORQ CX, CX
+
+invBlock:
+	VPBROADCASTW (SI), Y1
+	VPBROADCASTW (R8), Y2
+	SUBQ         $2, SI
+	SUBQ         $2, R8
+	MOVQ         CX, DX
+	LEAQ         (CX)(BX*1), R10
+
+invButterfly:
+	LEAQ    (DX)(BX*1), R11
+	VMOVDQU (DI)(DX*1), Y3
+	VMOVDQU (DI)(R11*1), Y4
+	INVBUTTERFLY(Y3, Y4, Y1, Y2, Y5, Y6)
+	VMOVDQU Y3, (DI)(DX*1)
+	VMOVDQU Y4, (DI)(R11*1)
+	ADDQ    $32, DX
+	CMPQ    DX, R10
+	JB      invButterfly
+	LEAQ    (CX)(BX*2), CX
+	CMPQ    CX, $512
+	JB      invBlock
+	SHLQ    $1, BX
+	CMPQ    BX, $256
+	JBE     invLayer
+
+	// Multiply every entry by 128⁻¹·R mod q = 512, note that 512·q⁻¹ ≡ 512 mod 2¹⁶.
+	XORQ DX, DX
+
+invScale:
+	VMOVDQU (DI)(DX*1), Y0
+	FQMUL(Y0, Y12, Y12, Y1, Y2)
+	CONDADDQ(Y1, Y2)
+	VMOVDQU Y1, (DI)(DX*1)
+	ADDQ    $32, DX
+	CMPQ    DX, $512
+	JB      invScale
+
+	VZEROUPPER
+	RET
+
+// func multiplyAccAVX2(h, a, b *polynomial)
+TEXT ·multiplyAccAVX2(SB), NOSPLIT, $0-24
+	MOVQ h+0(FP), DI
+	MOVQ a+8(FP), R8
+	MOVQ b+16(FP), R9
+	LEAQ ·gammasAVX2(SB), SI
+	BROADCASTW($3329, X15, Y15)
+	BROADCASTW($-3327, X14, Y14) // q⁻¹ mod 2¹⁶
+	BROADCASTD($3329, X13, Y13)  // q in the lower half of every 32-bit lane
+	XORQ DX, DX
+
+mulLoop:
+	VMOVDQU (R8)(DX*1), Y0 // a₀, a₁
+	VMOVDQU (R9)(DX*1), Y1 // b₀, b₁
+
+	// Y3 = fqmul(a₁, b₁) in the odd lanes.
+	VPMULLW Y1, Y0, Y2
+	VPMULLW Y14, Y2, Y2
+	VPMULHW Y1, Y0, Y3
+	VPMULHW Y15, Y2, Y2
+	VPSUBW  Y2, Y3, Y3
+
+	// c₀ = a₀·b₀ + fqmul(a₁, b₁)·γ and c₁ = a₀·b₁ + a₁·b₀ as 32-bit sums.
+	VPBLENDW $0xaa, Y3, Y0, Y2
+	VMOVDQU  (SI)(DX*1), Y4
+	VPBLENDW $0xaa, Y4, Y1, Y4
+	VPMADDWD Y4, Y2, Y2
+	VPSHUFLW $0xb1, Y1, Y1
+	VPSHUFHW $0xb1, Y1, Y1
+	VPMADDWD Y1, Y0, Y1
+
+	// Montgomery reduction: c − (c·q⁻¹ mod 2¹⁶)·q holds the result in its upper half.
+	VPMULLW  Y14, Y2, Y3
+	VPMADDWD Y13, Y3, Y3
+	VPSUBD   Y3, Y2, Y2
+	VPSRLD   $16, Y2, Y2
+	VPMULLW  Y14, Y1, Y3
+	VPMADDWD Y13, Y3, Y3
+	VPSUBD   Y3, Y1, Y1
+	VPBLENDW $0xaa, Y1, Y2, Y2
+
+	VPADDW  (DI)(DX*1), Y2, Y2
+	VMOVDQU Y2, (DI)(DX*1)
+	ADDQ    $32, DX
+	CMPQ    DX, $512
+	JB      mulLoop
+
+	VZEROUPPER
+	RET
+
+// func reduceAccAVX2(h *polynomial)
+TEXT ·reduceAccAVX2(SB), NOSPLIT, $0-8
+	MOVQ h+0(FP), DI
+	BROADCASTW($3329, X15, Y15)
+	BROADCASTW($1353, X14, Y14)  // R² mod q
+	BROADCASTW($20553, X13, Y13) // R²·q⁻¹ mod 2¹⁶
+	XORQ DX, DX
+
+reduceLoop:
+	VMOVDQU (DI)(DX*1), Y0
+	FQMUL(Y0, Y14, Y13, Y1, Y2)
+	CONDADDQ(Y1, Y2)
+	VMOVDQU Y1, (DI)(DX*1)
+	ADDQ    $32, DX
+	CMPQ    DX, $512
+	JB      reduceLoop
+
+	VZEROUPPER
+	RET
+
+// CBD2 widens 16 bytes of x − y + 2 to coefficients and stores them at off(DI).
+#define CBD2(x, off) \
+	VPMOVZXBW x, Y4;     \
+	VPSUBW    Y10, Y4, Y4; \
+	CONDADDQ(Y4, Y5);    \
+	VMOVDQU   Y4, off(DI)
+
+// func samplePolyCBD2AVX2(f *polynomial, b *[128]byte)
+TEXT ·samplePolyCBD2AVX2(SB), NOSPLIT, $0-16
+	MOVQ f+0(FP), DI
+	MOVQ b+8(FP), SI
+	BROADCASTW($3329, X15, Y15)
+	BROADCASTB($0x55, X14, Y14)
+	BROADCASTB($0x33, X13, Y13)
+	BROADCASTB($0x0f, X12, Y12)
+	BROADCASTB($0x22, X11, Y11)
+	BROADCASTW($2, X10, Y10)
+	XORQ DX, DX
+
+cbd2Loop:
+	// Every nibble of 32 bytes becomes x − y + 2 for one coefficient.
+	VMOVDQU (SI)(DX*1), Y0
+	VPSRLW  $1, Y0, Y1
+	VPAND   Y14, Y0, Y0
+	VPAND   Y14, Y1, Y1
+	VPADDB  Y1, Y0, Y0
+	VPSRLW  $2, Y0, Y1
+	VPAND   Y13, Y0, Y0
+	VPAND   Y13, Y1, Y1
+	VPADDB  Y11, Y0, Y0
+	VPSUBB  Y1, Y0, Y0
+	VPSRLW  $4, Y0, Y1
+	VPAND   Y12, Y0, Y0
+	VPAND   Y12, Y1, Y1
+
+	// Interleave the low and high nibbles.
+	VPUNPCKLBW Y1, Y0, Y2
+	VPUNPCKHBW Y1, Y0, Y3
+	CBD2(X2, 0)
+	CBD2(X3, 32)
+	VEXTRACTI128 $1, Y2, X2
+	VEXTRACTI128 $1, Y3, X3
+	CBD2(X2, 64)
+	CBD2(X3, 96)
+
+	ADDQ $128, DI
+	ADDQ $32, DX
+	CMPQ DX, $128
+	JB   cbd2Loop
+
+	VZEROUPPER
+	RET
+
+// cbd3Shuffle spreads 12 bytes of each 128-bit lane into four 32-bit lanes of 24 bits.
+// The upper lane is loaded 8 bytes ahead so that the last load stays within the input.
+DATA cbd3Shuffle<>+0x00(SB)/8, $0xff050403ff020100
+DATA cbd3Shuffle<>+0x08(SB)/8, $0xff0b0a09ff080706
+DATA cbd3Shuffle<>+0x10(SB)/8, $0xff090807ff060504
+DATA cbd3Shuffle<>+0x18(SB)/8, $0xff0f0e0dff0c0b0a
+GLOBL cbd3Shuffle<>(SB), RODATA|NOPTR, $32
+
+// func samplePolyCBD3AVX2(f *polynomial, b *[192]byte)
+TEXT ·samplePolyCBD3AVX2(SB), NOSPLIT, $0-16
+	MOVQ f+0(FP), DI
+	MOVQ b+8(FP), SI
+	BROADCASTW($3329, X15, Y15)
+	BROADCASTD($0x249249, X14, Y14)
+	BROADCASTD($7, X13, Y13)
+	BROADCASTD($0x70000, X12, Y12)
+	VMOVDQU cbd3Shuffle<>(SB), Y11
+	XORQ    DX, DX
+
+cbd3Loop:
+	VMOVDQU     (SI)(DX*1), X0
+	VINSERTI128 $1, 8(SI)(DX*1), Y0, Y0
+	VPSHUFB     Y11, Y0, Y0
+
+	// Sum each group of three bits.
+	VPSRLD $1, Y0, Y1
+	VPSRLD $2, Y0, Y2
+	VPAND  Y14, Y0, Y0
+	VPAND  Y14, Y1, Y1
+	VPAND  Y14, Y2, Y2
+	VPADDD Y1, Y0, Y0
+	VPADDD Y2, Y0, Y0
+
+	// Coefficients 0 and 1 of each 32-bit lane: x at bits 0 and 6, y at bits 3 and 9.
+	VPAND  Y13, Y0, Y1
+	VPSLLD $10, Y0, Y2
+	VPAND  Y12, Y2, Y2
+	VPOR   Y2, Y1, Y1
+	VPSRLD $3, Y0, Y2
+	VPAND  Y13, Y2, Y2
+	VPSLLD $7, Y0, Y3
+	VPAND  Y12, Y3, Y3
+	VPOR   Y3, Y2, Y2
+	VPSUBW Y2, Y1, Y1
+
+	// Coefficients 2 and 3: x at bits 12 and 18, y at bits 15 and 21.
+	VPSRLD $12, Y0, Y2
+	VPAND  Y13, Y2, Y2
+	VPSRLD $2, Y0, Y3
+	VPAND  Y12, Y3, Y3
+	VPOR   Y3, Y2, Y2
+	VPSRLD $15, Y0, Y3
+	VPAND  Y13, Y3, Y3
+	VPSRLD $5, Y0, Y4
+	VPAND  Y12, Y4, Y4
+	VPOR   Y4, Y3, Y3
+	VPSUBW Y3, Y2, Y2
+
+	CONDADDQ(Y1, Y3)
+	CONDADDQ(Y2, Y3)
+	VPUNPCKLDQ Y2, Y1, Y3
+	VPUNPCKHDQ Y2, Y1, Y4
+	VPERM2I128 $0x20, Y4, Y3, Y0
+	VPERM2I128 $0x31, Y4, Y3, Y1
+	VMOVDQU    Y0, (DI)
+	VMOVDQU    Y1, 32(DI)
+
+	ADDQ $64, DI
+	ADDQ $24, DX
+	CMPQ DX, $192
+	JB   cbd3Loop
+
+	VZEROUPPER
+	RET
+
+// COMPRESS8 sets the dwords of y to the compressed values of the 8 coefficients at m.
+// compress is ⌊(x·2ᵈ + ⌊q/2⌋)·⌈2³⁵/q⌉ / 2³⁵⌋ mod 2ᵈ.
+#define COMPRESS8(m, y) \
+	VPMOVZXWD m, y;        \
+	VPSLLD    X9, y, y;    \
+	VPADDD    Y14, y, y;   \
+	VPMULUDQ  Y13, y, Y1;  \
+	VPSRLQ    $32, y, Y2;  \
+	VPMULUDQ  Y13, Y2, Y2; \
+	VPSRLQ    $35, Y1, Y1; \
+	VPSRLQ    $35, Y2, Y2; \
+	VPAND     Y12, Y1, Y1; \
+	VPAND     Y12, Y2, Y2; \
+	VPSLLQ    $32, Y2, Y2; \
+	VPOR      Y2, Y1, y
+
+// func compressAVX2(b *compressed, f *polynomial, d int)
+TEXT ·compressAVX2(SB), NOSPLIT, $0-24
+	MOVQ  b+0(FP), DI
+	MOVQ  f+8(FP), SI
+	MOVQ  d+16(FP), CX
+	VMOVQ CX, X9
+	BROADCASTD($1664, X14, Y14)     // ⌊q/2⌋
+	BROADCASTD($10321340, X13, Y13) // ⌈2³⁵/q⌉
+	MOVQ  $1, AX
+	SHLQ  CX, AX
+	DECQ  AX
+	VMOVQ AX, X12
+	VPBROADCASTQ X12, Y12 // 2ᵈ − 1
+	XORQ  DX, DX
+
+compressLoop:
+	COMPRESS8((SI)(DX*1), Y0)
+	COMPRESS8(16(SI)(DX*1), Y3)
+	VPACKUSDW Y3, Y0, Y0
+	VPERMQ    $0xd8, Y0, Y0
+	VMOVDQU   Y0, (DI)(DX*1)
+
+	ADDQ $32, DX
+	CMPQ DX, $512
+	JB   compressLoop
+
+	VZEROUPPER
+	RET
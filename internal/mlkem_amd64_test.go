@@ -0,0 +1,124 @@
+//go:build !purego
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"testing/quick"
+)
+
+func TestAVX2(t *testing.T) {
+	if !useAVX2 {
+		t.Skip("AVX2 is not supported")
+	}
+	var max polynomial
+	for i := range max {
+		max[i] = q - 1
+	}
+
+	t.Run("NTT", func(t *testing.T) {
+		f := func(f polynomial) bool {
+			g := f
+			nttAVX2(&f)
+			g.nttGeneric()
+			return f == g
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+		if !f(polynomial{}) || !f(max) {
+			t.Error("mismatch for extremes")
+		}
+	})
+	t.Run("NTTinv", func(t *testing.T) {
+		f := func(f polynomial) bool {
+			g := f
+			nttInvAVX2(&f)
+			g.nttInvGeneric()
+			return f == g
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+		if !f(polynomial{}) || !f(max) {
+			t.Error("mismatch for extremes")
+		}
+	})
+	t.Run("multiplyAcc", func(t *testing.T) {
+		f := func(a, b, c, d polynomial) bool {
+			var h1, h2 polynomial
+			multiplyAccAVX2(&h1, &a, &b)
+			multiplyAccAVX2(&h1, &c, &d)
+			h2.multiplyAccGeneric(&a, &b)
+			h2.multiplyAccGeneric(&c, &d)
+			if h1 != h2 {
+				return false
+			}
+			reduceAccAVX2(&h1)
+			h2.reduceAccGeneric()
+			return h1 == h2
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+		if !f(max, max, max, max) {
+			t.Error("mismatch for extremes")
+		}
+	})
+	t.Run("samplePolyCBD", func(t *testing.T) {
+		f2 := func(b [64 * 2]byte) bool {
+			var f, g polynomial
+			samplePolyCBD2AVX2(&f, &b)
+			g.samplePolyCBDGeneric(b[:])
+			return f == g
+		}
+		if err := quick.Check(f2, nil); err != nil {
+			t.Error(err)
+		}
+		f3 := func(b [64 * 3]byte) bool {
+			var f, g polynomial
+			samplePolyCBD3AVX2(&f, &b)
+			g.samplePolyCBDGeneric(b[:])
+			return f == g
+		}
+		if err := quick.Check(f3, nil); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("Compress", func(t *testing.T) {
+		for d := 1; d <= 11; d++ {
+			t.Run(fmt.Sprintf("d=%d", d), func(t *testing.T) {
+				f := func(f polynomial) bool {
+					var b1, b2 compressed
+					compressAVX2(&b1, &f, d)
+					polyCompressGeneric(&b2, &f, d)
+					return b1 == b2
+				}
+				if err := quick.Check(f, nil); err != nil {
+					t.Error(err)
+				}
+				var all polynomial
+				for x := 0; x < q; x += 256 {
+					for i := range all {
+						all[i] = int16(min(x+i, q-1))
+					}
+					if !f(all) {
+						t.Fatalf("mismatch for %d", x)
+					}
+				}
+			})
+		}
+	})
+	t.Run("Keccak", func(t *testing.T) {
+		f := func(s keccak4) bool {
+			g := s
+			keccakF1600x4AVX2(&s)
+			g.permuteGeneric()
+			return s == g
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+//go:build !amd64 || purego
+
+package internal
+
+func (f *polynomial) ntt() {
+	f.nttGeneric()
+}
+
+func (f *polynomial) nttInv() {
+	f.nttInvGeneric()
+}
+
+func (h *polynomial) multiplyAcc(f, g *polynomial) {
+	h.multiplyAccGeneric(f, g)
+}
+
+func (h *polynomial) reduceAcc() {
+	h.reduceAccGeneric()
+}
+
+func (f *polynomial) samplePolyCBD(b []byte) {
+	f.samplePolyCBDBitsliced(b)
+}
+
+func (f *polynomial) samplePolyCBDPRF(s []byte, b byte, eta int) {
+	f.samplePolyCBDPRFGeneric(s, b, eta)
+}
+
+func polyCompress(b *compressed, f *polynomial, d int) {
+	polyCompressGeneric(b, f, d)
+}
+
+func (f *polynomial) compressEncode(b []byte, d int) {
+	f.compressEncodeGeneric(b, d)
+}
+
+func (s *keccak4) permute() {
+	s.permuteGeneric()
+}
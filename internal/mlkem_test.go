@@ -1,10 +1,10 @@
 package internal
 
 import (
-	"bytes"
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"math"
 	mrand "math/rand"
 	"reflect"
 	"testing"
@@ -13,9 +13,10 @@ import (
 
 func (polynomial) Generate(*mrand.Rand, int) reflect.Value {
 	var f polynomial
-	binary.Read(rand.Reader, binary.NativeEndian, &f)
+	var u [256]uint16
+	binary.Read(rand.Reader, binary.NativeEndian, &u)
 	for i := range f {
-		f[i] %= q
+		f[i] = int16(u[i] % q)
 	}
 	return reflect.ValueOf(f)
 }
@@ -28,11 +29,11 @@ func modPow2d(v *[256]uint, d int) {
 
 func multiply(f, g polynomial) polynomial {
 	const n = len(polynomial{})
-	var t [n * 2]uintq
+	var t [n * 2]int16
 	for i := range n {
 		for j := range n {
 			fg := uintq2(f[i]) * uintq2(g[j]) % q
-			t[i+j] = (t[i+j] + uintq(fg)) % q
+			t[i+j] = (t[i+j] + int16(fg)) % q
 		}
 	}
 	for i := range n {
@@ -108,7 +109,7 @@ func TestMultiplyNTTs(t *testing.T) {
 }
 
 func TestSamplePolyCBD(t *testing.T) {
-	testBinominal := func(f polynomial, eta uintq) bool {
+	testBinominal := func(f polynomial, eta int16) bool {
 		// 0 ≤ 𝑓[𝑖] ≤ 𝜂 or 𝑞 − 𝜂 ≤ 𝑓[𝑖] ≤ 𝑞 − 1
 		for i := range f {
 			if !((f[i] <= eta) || (q-eta <= f[i] && f[i] <= q-1)) {
@@ -137,6 +138,37 @@ func TestSamplePolyCBD(t *testing.T) {
 		}
 	})
 
+	t.Run("bitsliced", func(t *testing.T) {
+		for _, eta := range []int{2, 3} {
+			f := func(b [64 * 3]byte) bool {
+				var f, g polynomial
+				f.samplePolyCBDBitsliced(b[:64*eta])
+				g.samplePolyCBDGeneric(b[:64*eta])
+				return f == g && testBinominal(f, int16(eta))
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Errorf("eta=%d: %v", eta, err)
+			}
+		}
+	})
+
+	t.Run("PRF", func(t *testing.T) {
+		for _, eta := range []int{2, 3} {
+			f := func(s [32]byte, n byte) bool {
+				var prf [64 * 3]byte
+				PRF(prf[:64*eta], s[:], n)
+				want := SamplePolyCBD(prf[:64*eta])
+				var f, g polynomial
+				f.samplePolyCBDPRF(s[:], n, eta)
+				g.samplePolyCBDPRFGeneric(s[:], n, eta)
+				return f == want && g == want
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Errorf("eta=%d: %v", eta, err)
+			}
+		}
+	})
+
 	t.Run("allocs", func(t *testing.T) {
 		b := make([]byte, 64*3)
 		rand.Read(b)
@@ -192,6 +224,23 @@ func TestByteEncodeDecode(t *testing.T) {
 		}
 	})
 
+	t.Run("modulus", func(t *testing.T) {
+		f := func(f polynomial, i uint8) bool {
+			b := ByteEncodeQ(f)
+			if !CheckModulus(b) {
+				return false
+			}
+			// Replace coefficient 2i with q.
+			n := 3 * int(i%128)
+			b[n] = q & 0xff
+			b[n+1] = b[n+1]&0xf0 | q>>8
+			return !CheckModulus(b)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
 	t.Run("d", func(t *testing.T) {
 		for i := range 11 {
 			d := i + 1
@@ -224,7 +273,7 @@ func TestCompressDecompress(t *testing.T) {
 	})
 
 	t.Run("compress-decompress", func(t *testing.T) {
-		absDiff := func(a, b uintq) uintq {
+		absDiff := func(a, b int16) int16 {
 			d := a - b
 			if b > a {
 				d = b - a
@@ -278,13 +327,16 @@ func TestKPKE(t *testing.T) {
 	} {
 		t.Run(p.name, func(t *testing.T) {
 			f := func(d, r, m [32]byte) bool {
-				ekPKE, dkPKE := KPKEKeyGen(d[:], p.k, p.eta1)
+				ekPKE, dkPKE := make([]byte, 384*p.k+32), make([]byte, 384*p.k)
+				KPKEKeyGen(ekPKE, dkPKE, d[:], p.k, p.eta1)
 
-				c := KPKEEncrypt(ekPKE, m[:], r[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+				c := make([]byte, 32*(p.du*p.k+p.dv))
+				KPKEEncrypt(c, ekPKE, m[:], r[:], p.k, p.eta1, p.eta2, p.du, p.dv)
 
-				dm := KPKEDecrypt(dkPKE, c, p.k, p.du, p.dv)
+				var dm [32]byte
+				KPKEDecrypt(dm[:], dkPKE, c, p.k, p.du, p.dv)
 
-				return bytes.Equal(m[:], dm)
+				return m == dm
 			}
 			if err := quick.Check(f, nil); err != nil {
 				t.Error(err)
@@ -303,18 +355,253 @@ func TestInternal(t *testing.T) {
 		{name: "ML-KEM-1024", k: 4, eta1: 2, eta2: 2, du: 11, dv: 5},
 	} {
 		t.Run(p.name, func(t *testing.T) {
+			ek, dk := make([]byte, 384*p.k+32), make([]byte, 768*p.k+96)
+			c := make([]byte, 32*(p.du*p.k+p.dv))
+			var K, K_ [32]byte
+
 			f := func(d, z, m [32]byte) bool {
-				ek, dk := KeyGen_internal(d[:], z[:], p.k, p.eta1)
+				KeyGen_internal(ek, dk, d[:], z[:], p.k, p.eta1)
 
-				K, c := Encaps_internal(ek, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+				Encaps_internal(K[:], c, ek, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
 
-				K_ := Decaps_internal(dk, c, p.k, p.eta1, p.eta2, p.du, p.dv)
+				Decaps_internal(K_[:], dk, c, p.k, p.eta1, p.eta2, p.du, p.dv)
 
-				return bytes.Equal(K, K_)
+				return K == K_
 			}
 			if err := quick.Check(f, nil); err != nil {
 				t.Error(err)
 			}
+
+			avg := testing.AllocsPerRun(10, func() {
+				var d, z, m [32]byte
+				KeyGen_internal(ek, dk, d[:], z[:], p.k, p.eta1)
+				Encaps_internal(K[:], c, ek, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+				Decaps_internal(K_[:], dk, c, p.k, p.eta1, p.eta2, p.du, p.dv)
+			})
+			if avg > 0 {
+				t.Errorf("Non-zero allocs: %f", avg)
+			}
 		})
 	}
 }
+
+func TestReduce(t *testing.T) {
+	mod := func(a int32) int32 {
+		return (a%q + q) % q
+	}
+	t.Run("reduceOnce", func(t *testing.T) {
+		for a := range int16(2 * q) {
+			if r := reduceOnce(a); r != a%q {
+				t.Fatalf("reduceOnce(%d) = %d, expected %d", a, r, a%q)
+			}
+		}
+	})
+	t.Run("condAddQ", func(t *testing.T) {
+		for a := int16(-q); a < q; a++ {
+			if r := condAddQ(a); int32(r) != mod(int32(a)) {
+				t.Fatalf("condAddQ(%d) = %d, expected %d", a, r, mod(int32(a)))
+			}
+		}
+	})
+	t.Run("barrettReduce", func(t *testing.T) {
+		for a := int32(math.MinInt16); a <= math.MaxInt16; a++ {
+			r := barrettReduce(int16(a))
+			if r < -(q-1)/2 || r > (q-1)/2 || mod(int32(r)) != mod(a) {
+				t.Fatalf("barrettReduce(%d) = %d", a, r)
+			}
+			if c := canonical(int16(a)); int32(c) != mod(a) {
+				t.Fatalf("canonical(%d) = %d, expected %d", a, c, mod(a))
+			}
+		}
+	})
+	t.Run("montgomeryReduce", func(t *testing.T) {
+		const rInv = 169 // R⁻¹ mod q
+		f := func(a int32) bool {
+			a %= q << 15
+			r := montgomeryReduce(a)
+			return -q < r && r < q && mod(int32(r)) == mod(mod(a)*rInv)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+		if r := fqmul(rModQ, 1); r != 1 {
+			t.Errorf("fqmul(R, 1) = %d, expected 1", r)
+		}
+		if r := fqmul(r2ModQ, 1); mod(int32(r)) != rModQ {
+			t.Errorf("fqmul(R², 1) = %d, expected %d", r, rModQ)
+		}
+	})
+}
+
+func TestDecapsImplicitRejection(t *testing.T) {
+	const k, eta1, eta2, du, dv = 3, 2, 2, 10, 4
+	f := func(d, z, m [32]byte, i uint16) bool {
+		ek, dk := make([]byte, 384*k+32), make([]byte, 768*k+96)
+		KeyGen_internal(ek, dk, d[:], z[:], k, eta1)
+		var K [32]byte
+		c := make([]byte, 32*(du*k+dv))
+		Encaps_internal(K[:], c, ek, m[:], k, eta1, eta2, du, dv)
+
+		c[int(i)%len(c)] ^= 1
+		Decaps_internal(K[:], dk, c, k, eta1, eta2, du, dv)
+
+		return K == J(z[:], c)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompressDivisionFree(t *testing.T) {
+	// Reference implementation of Compress and Decompress using division (FIPS 203 §4.2.1).
+	compressDiv := func(x int16, d int) uint {
+		pow2d := uintq2(1 << d)
+		return uint(((uintq2(x)*pow2d + q/2) / q) % pow2d)
+	}
+	decompressDiv := func(y uint, d int) int16 {
+		pow2d := uintq2(1 << d)
+		return int16((uintq2(y)*q + pow2d/2) / pow2d)
+	}
+	for d := 1; d <= 11; d++ {
+		t.Run(fmt.Sprintf("d=%d", d), func(t *testing.T) {
+			for x := range int16(q) {
+				if got, want := compress(x, d), compressDiv(x, d); got != want {
+					t.Fatalf("compress(%d, %d) = %d, expected %d", x, d, got, want)
+				}
+			}
+			for y := range uint(1 << d) {
+				var b [256]uint
+				b[0] = y
+				if got, want := Decompress(b, d)[0], decompressDiv(y, d); got != want {
+					t.Fatalf("Decompress(%d, %d) = %d, expected %d", y, d, got, want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNTT(b *testing.B) {
+	f := SampleNTT(make([]byte, 32), 0, 0)
+	for b.Loop() {
+		f = NTT(f)
+	}
+}
+
+func BenchmarkNTTinv(b *testing.B) {
+	f := SampleNTT(make([]byte, 32), 0, 0)
+	for b.Loop() {
+		f = NTTinv(f)
+	}
+}
+
+func BenchmarkMultiplyNTTs(b *testing.B) {
+	f := SampleNTT(make([]byte, 32), 0, 0)
+	g := SampleNTT(make([]byte, 32), 0, 1)
+	for b.Loop() {
+		f = MultiplyNTTs(f, g)
+	}
+}
+
+// Reference implementations of NTT, NTTinv and MultiplyNTTs
+// following FIPS 203 Algorithms 9–12 with modular reduction after every operation.
+
+func powModQ(a, e int) int {
+	r := 1
+	for range e {
+		r = r * a % q
+	}
+	return r
+}
+
+func bitRev7(i int) int {
+	var r int
+	for j := range 7 {
+		r |= (i >> j & 1) << (6 - j)
+	}
+	return r
+}
+
+func nttReference(f polynomial) polynomial {
+	i := 1
+	for len := 128; len >= 2; len /= 2 {
+		for start := 0; start < 256; start += 2 * len {
+			zeta := powModQ(17, bitRev7(i))
+			i++
+			for j := start; j < start+len; j++ {
+				t := zeta * int(f[j+len]) % q
+				f[j+len] = int16((q + int(f[j]) - t) % q)
+				f[j] = int16((int(f[j]) + t) % q)
+			}
+		}
+	}
+	return f
+}
+
+func nttInvReference(f polynomial) polynomial {
+	i := 127
+	for len := 2; len <= 128; len *= 2 {
+		for start := 0; start < 256; start += 2 * len {
+			zeta := powModQ(17, bitRev7(i))
+			i--
+			for j := start; j < start+len; j++ {
+				t := int(f[j])
+				f[j] = int16((t + int(f[j+len])) % q)
+				f[j+len] = int16(zeta * (q + int(f[j+len]) - t) % q)
+			}
+		}
+	}
+	for i := range f {
+		f[i] = int16(int(f[i]) * 3303 % q)
+	}
+	return f
+}
+
+func multiplyNTTsReference(f, g polynomial) polynomial {
+	var h polynomial
+	for i := range 128 {
+		gamma := powModQ(17, 2*bitRev7(i)+1)
+		a0, a1, b0, b1 := int(f[2*i]), int(f[2*i+1]), int(g[2*i]), int(g[2*i+1])
+		h[2*i] = int16((a0*b0 + a1*b1%q*gamma) % q)
+		h[2*i+1] = int16((a0*b1 + a1*b0) % q)
+	}
+	return h
+}
+
+func TestReference(t *testing.T) {
+	t.Run("NTT", func(t *testing.T) {
+		f := func(f polynomial) bool {
+			return NTT(f) == nttReference(f)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("NTTinv", func(t *testing.T) {
+		f := func(f polynomial) bool {
+			return NTTinv(f) == nttInvReference(f)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("MultiplyNTTs", func(t *testing.T) {
+		f := func(f, g polynomial) bool {
+			return MultiplyNTTs(f, g) == multiplyNTTsReference(f, g)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("extremes", func(t *testing.T) {
+		var zero, max polynomial
+		for i := range max {
+			max[i] = q - 1
+		}
+		for _, f := range []polynomial{zero, max} {
+			if NTT(f) != nttReference(f) || NTTinv(f) != nttInvReference(f) ||
+				MultiplyNTTs(f, f) != multiplyNTTsReference(f, f) {
+				t.Errorf("mismatch for %v", f[0])
+			}
+		}
+	})
+}
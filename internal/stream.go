@@ -0,0 +1,152 @@
+package internal
+
+import "crypto/subtle"
+
+// The functions in this file are low-memory variants of K-PKE and ML-KEM with identical outputs.
+// They never materialize the matrix Â: each entry is sampled when it is needed
+// and multiplied into an accumulator, row by row for Â × ŝ and column by column for Âᵀ × ŷ.
+// The vectors t̂ and ŝ are decoded from their encodings one polynomial at a time,
+// so apart from ŷ the peak memory is a few polynomials.
+
+// KPKEKeyGenStreaming is like KPKEKeyGen but does not materialize Â.
+func KPKEKeyGenStreaming(ekPKE, dkPKE, d []byte, k, eta1 int) {
+	ro, sigma := G(d, []byte{byte(k)})
+
+	var s_ polynomial
+	var N byte
+	for i := range k {
+		s_.samplePolyCBDPRF(sigma[:], N, eta1)
+		s_.ntt()
+		s_.byteEncodeQ(dkPKE[384*i : 384*(i+1)])
+		N++
+	}
+	var t_, a, e_ polynomial
+	for i := range k {
+		// t̂[i] = Σⱼ Â[i][j] × ŝ[j] + ê[i]
+		t_ = polynomial{}
+		for j := range k {
+			a.sampleNTT(ro[:], byte(j), byte(i))
+			s_.byteDecodeQ(dkPKE[384*j : 384*(j+1)])
+			t_.multiplyAcc(&a, &s_)
+		}
+		t_.reduceAcc()
+		e_.samplePolyCBDPRF(sigma[:], N, eta1)
+		e_.ntt()
+		t_.add(&e_)
+		N++
+		t_.byteEncodeQ(ekPKE[384*i : 384*(i+1)])
+	}
+	copy(ekPKE[384*k:], ro[:])
+	clear(sigma[:])
+	s_, e_ = polynomial{}, polynomial{}
+}
+
+// KPKEEncryptStreaming is like KPKEEncrypt but does not materialize Â.
+func KPKEEncryptStreaming(c, ekPKE, m, r []byte, k, eta1, eta2, du, dv int) {
+	ro := ekPKE[384*k : 384*k+32]
+
+	var N byte
+	var y_ [maxK]polynomial
+	for i := range k {
+		y_[i].samplePolyCBDPRF(r, N, eta1)
+		y_[i].ntt()
+		N++
+	}
+
+	var u, a, e polynomial
+	for i := range k {
+		// u[i] = NTT⁻¹(Σⱼ Âᵀ[i][j] × ŷ[j]) + e₁[i]
+		u = polynomial{}
+		for j := range k {
+			a.sampleNTT(ro, byte(i), byte(j)) // Â[j][i]
+			u.multiplyAcc(&a, &y_[j])
+		}
+		u.reduceAcc()
+		u.nttInv()
+		e.samplePolyCBDPRF(r, N, eta2)
+		u.add(&e)
+		N++
+		u.compressEncode(c[32*du*i:32*du*(i+1)], du)
+	}
+
+	// v = NTT⁻¹(t̂ᵀ × ŷ) + e₂ + μ
+	v := &u
+	*v = polynomial{}
+	for j := range k {
+		a.byteDecodeQ(ekPKE[384*j : 384*(j+1)])
+		v.multiplyAcc(&a, &y_[j])
+	}
+	v.reduceAcc()
+	v.nttInv()
+	e.samplePolyCBDPRF(r, N, eta2)
+	v.add(&e)
+	a.decodeDecompress(m, 1)
+	v.add(&a)
+	v.compressEncode(c[32*du*k:32*(du*k+dv)], dv)
+	clear(y_[:])
+	u, a, e = polynomial{}, polynomial{}, polynomial{}
+}
+
+// KPKEDecryptStreaming is like KPKEDecrypt but decodes ŝ one polynomial at a time.
+func KPKEDecryptStreaming(m, dkPKE, c []byte, k, du, dv int) {
+	c1 := c[0 : 32*du*k]
+	c2 := c[32*du*k : 32*(du*k+dv)]
+
+	// w = v − NTT⁻¹(ŝᵀ × NTT(u))
+	var u, s_, w polynomial
+	for i := range k {
+		u.decodeDecompress(c1[32*du*i:32*du*(i+1)], du)
+		u.ntt()
+		s_.byteDecodeQ(dkPKE[384*i : 384*(i+1)])
+		w.multiplyAcc(&s_, &u)
+	}
+	w.reduceAcc()
+	w.nttInv()
+	v := &u
+	v.decodeDecompress(c2, dv)
+	v.sub(&w)
+	v.compressEncode(m, 1)
+	u, s_, w = polynomial{}, polynomial{}, polynomial{}
+}
+
+// KeyGenStreaming_internal is like KeyGen_internal but does not materialize Â.
+func KeyGenStreaming_internal(ek, dk, d, z []byte, k, eta1 int) {
+	KPKEKeyGenStreaming(ek[:384*k+32], dk[:384*k], d, k, eta1)
+	copy(dk[384*k:], ek[:384*k+32])
+	h := H(ek[:384*k+32])
+	copy(dk[768*k+32:], h[:])
+	copy(dk[768*k+64:], z)
+}
+
+// EncapsStreaming_internal is like Encaps_internal but does not materialize Â.
+func EncapsStreaming_internal(K, c, ek, m []byte, k, eta1, eta2, du, dv int) {
+	h := H(ek)
+	K_, r := G(m, h[:])
+	KPKEEncryptStreaming(c, ek, m, r[:], k, eta1, eta2, du, dv)
+	copy(K, K_[:])
+	clear(K_[:])
+	clear(r[:])
+}
+
+// DecapsStreaming_internal is like Decaps_internal but does not materialize Â.
+func DecapsStreaming_internal(K, dk, c []byte, k, eta1, eta2, du, dv int) {
+	dkPKE := dk[0 : 384*k]
+	ekPKE := dk[384*k : 768*k+32]
+	h := dk[768*k+32 : 768*k+64]
+	z := dk[768*k+64 : 768*k+96]
+
+	var m [32]byte
+	KPKEDecryptStreaming(m[:], dkPKE, c, k, du, dv)
+	K_, r := G(m[:], h)
+	Kbar := J(z, c)
+	var c_ [32 * (11*maxK + 5)]byte
+	KPKEEncryptStreaming(c_[:len(c)], ekPKE, m[:], r[:], k, eta1, eta2, du, dv)
+	eq := subtle.ConstantTimeCompare(c, c_[:len(c)])
+	subtle.ConstantTimeCopy(1-eq, K_[:], Kbar[:])
+	copy(K, K_[:])
+	clear(m[:])
+	clear(K_[:])
+	clear(r[:])
+	clear(Kbar[:])
+	clear(c_[:])
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+)
+
+func TestStreaming(t *testing.T) {
+	for _, p := range []struct {
+		name                  string
+		k, eta1, eta2, du, dv int
+	}{
+		{name: "ML-KEM-512", k: 2, eta1: 3, eta2: 2, du: 10, dv: 4},
+		{name: "ML-KEM-768", k: 3, eta1: 2, eta2: 2, du: 10, dv: 4},
+		{name: "ML-KEM-1024", k: 4, eta1: 2, eta2: 2, du: 11, dv: 5},
+	} {
+		t.Run(p.name, func(t *testing.T) {
+			ek1, dk1 := make([]byte, 384*p.k+32), make([]byte, 768*p.k+96)
+			ek2, dk2 := make([]byte, 384*p.k+32), make([]byte, 768*p.k+96)
+			c1, c2 := make([]byte, 32*(p.du*p.k+p.dv)), make([]byte, 32*(p.du*p.k+p.dv))
+			var K1, K2, K3, K4 [32]byte
+
+			f := func(d, z, m [32]byte, flip uint16) bool {
+				KeyGen_internal(ek1, dk1, d[:], z[:], p.k, p.eta1)
+				KeyGenStreaming_internal(ek2, dk2, d[:], z[:], p.k, p.eta1)
+				if !bytes.Equal(ek1, ek2) || !bytes.Equal(dk1, dk2) {
+					return false
+				}
+
+				Encaps_internal(K1[:], c1, ek1, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+				EncapsStreaming_internal(K2[:], c2, ek1, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+				if K1 != K2 || !bytes.Equal(c1, c2) {
+					return false
+				}
+
+				// Valid and implicitly rejected ciphertexts.
+				for range 2 {
+					Decaps_internal(K3[:], dk1, c1, p.k, p.eta1, p.eta2, p.du, p.dv)
+					DecapsStreaming_internal(K4[:], dk1, c1, p.k, p.eta1, p.eta2, p.du, p.dv)
+					if K3 != K4 {
+						return false
+					}
+					c1[int(flip)%len(c1)] ^= 1
+				}
+				return K1 != K3
+			}
+			if err := quick.Check(f, nil); err != nil {
+				t.Error(err)
+			}
+
+			avg := testing.AllocsPerRun(10, func() {
+				var d, z, m [32]byte
+				KeyGenStreaming_internal(ek2, dk2, d[:], z[:], p.k, p.eta1)
+				EncapsStreaming_internal(K2[:], c2, ek2, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+				DecapsStreaming_internal(K4[:], dk2, c2, p.k, p.eta1, p.eta2, p.du, p.dv)
+			})
+			if avg > 0 {
+				t.Errorf("Non-zero allocs: %f", avg)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"crypto/sha3"
+	"encoding/hex"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+// knownAnswerTests are the ML-KEM steps of the HPKE test vectors of
+// draft-ietf-hpke-pq, as shipped in the Go distribution in
+// src/crypto/hpke/testdata/hpke-pq.json: skRm is the d‖z seed,
+// ikmE is the encapsulation randomness m, enc is the ciphertext
+// and shared_secret is the shared key.
+// The encapsulation key pkRm and the ciphertext enc are stored as their SHA3-256 hashes.
+var knownAnswerTests = []struct {
+	p                 mlkem.ParameterSet
+	seed, m, ek, c, K string
+}{
+	{
+		p:    mlkem.MLKEM_512,
+		seed: "ba0f0c4af2328dc89ec354c6b59c3714626773daf08f2d7e249309d9c331cc0f055b007c6947d28bfc52cc1e6af7086cd5db100a8147a4857615a4cd1e83ca63",
+		m:    "b0451916702d592d6358f6306f9e3ac1f5dc3329014f00d416fc231e4cb0b21b",
+		ek:   "c2255ddb7b49a5986fd1de6fef8765a33b1fc1a6c8cdf8d37e3bb80a42b468ce",
+		c:    "1e9401ed982775318f1d85e6d8a7cd8e5eab1c03cac16279fda8620656e50ab2",
+		K:    "2fc9533e0ba8e59f0753280bc099674320bae39a0d4f817b6271789b2f4aef33",
+	},
+	{
+		p:    mlkem.MLKEM_768,
+		seed: "3530176644619eb968895c1a251e8568e063278a7d9f4314b7d0ad973be2fd0b9560e77a2ca3f07958d782cab43cbae46e16bbc90277545d333e11ddcf18df61",
+		m:    "54274849d6fa9d1c71d658b4bcdec56bba6a4a49e0178fe4639d321920c258c0",
+		ek:   "8ccefdebbe65c2eccbf3f26b6250b2b02ce7854c1d9badfaa6563296c9c1c68d",
+		c:    "6fb4b11babdb41e67f8304b5bc8e2a2f327e4a33c43dafc233e6fc05972d6266",
+		K:    "02a5ae918c2061093153b64a9ab0e7fd0557b83c525ae40b5105445562acf451",
+	},
+	{
+		p:    mlkem.MLKEM_1024,
+		seed: "f279454d08150d5bd81252001d02e1099f12fb7e9be6da2fe427bbaa2d79b0ab67306c0153c052610c4fdba3fad3435aeb1b65817d442c5c18ce07ea42440005",
+		m:    "b79ccf36c6d61fb48511de939a6a23be436eb9c744bdbd3a6aab85bcad61377b",
+		ek:   "564d71aee5ec3d57776c9a401c2009dfae2bdc26af77e3547369a4a58cc054a1",
+		c:    "2ffa98aa60523f73ae3e3d1266af39d9f95729d4b5c877ffed1cc59766553687",
+		K:    "82e39853d199735aa5bf8fb3fbee412de8b39ae39cbad0bd7326c3cf1f6c6232",
+	},
+	{
+		p:    mlkem.MLKEM_1024,
+		seed: "d919b835b98c968e6a1e85a6e7c54be8774df0a4d00775626dea7c0fd0750d92fa2c8655a59401910e2da51f5bf78b2014840fed74753f760ac85586c0891570",
+		m:    "a20456fa8c2a558686a231eea685c004974b90f53a716e9cc1716ddab8619efe",
+		ek:   "0fcac83e9a176352ec2cb7bb2a9fb3d6d50255166a7a0a1afee7095933b9e669",
+		c:    "ce0a1423a6dd17478d2588592a03c73f40b845ee7cf6dd8b05223f5a0b0f89f1",
+		K:    "c2575ffa4d3ac41eb1b7e31cff87172128a33a0f3065313351cb8e556e13a9b1",
+	},
+}
+
+func TestKnownAnswer(t *testing.T) {
+	for _, tc := range knownAnswerTests {
+		t.Run(tc.p.String(), func(t *testing.T) {
+			seed, m := mustDecodeHex(t, tc.seed), mustDecodeHex(t, tc.m)
+			ek, dk, err := tc.p.KeyGenDerand(seed[:32], seed[32:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sha3.Sum256(ek); hex.EncodeToString(got[:]) != tc.ek {
+				t.Errorf("ek hash: got %x, expected %s", got, tc.ek)
+			}
+			K, c, err := tc.p.EncapsDerand(ek, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sha3.Sum256(c); hex.EncodeToString(got[:]) != tc.c {
+				t.Errorf("c hash: got %x, expected %s", got, tc.c)
+			}
+			if !bytes.Equal(K, mustDecodeHex(t, tc.K)) {
+				t.Errorf("K: got %x, expected %s", K, tc.K)
+			}
+			if K2, err := tc.p.Decaps(dk, c); err != nil || !bytes.Equal(K2, K) {
+				t.Errorf("Decaps: got %x, %v", K2, err)
+			}
+			if K3, c3, err := tc.p.LowMemory().EncapsDerand(ek, m); err != nil || !bytes.Equal(K3, K) || !bytes.Equal(c3, c) {
+				t.Errorf("LowMemory.EncapsDerand: got %x, %v", K3, err)
+			}
+		})
+	}
+}
+
+// TestAccumulated follows TestAccumulated of crypto/mlkem in the Go distribution,
+// which checks the hash of the accumulated C2SP/CCTV ML-KEM-768 vectors:
+// keys and ciphertexts derived from a SHAKE128 stream, including the implicit rejection
+// of random ciphertexts.
+func TestAccumulated(t *testing.T) {
+	n := 10000
+	expected := "8a518cc63da366322a8e7a818c7a0d63483cb3528d34a4cf42f35d5ad73f22fc"
+	if testing.Short() {
+		n = 100
+		expected = "1114b1b6699ed191734fa339376afa7e285c9e6acf6ff0177d346696ce564415"
+	}
+
+	p := mlkem.MLKEM_768
+	s := sha3.NewSHAKE128()
+	o := sha3.NewSHAKE128()
+	seed := make([]byte, mlkem.SeedSize)
+	m := make([]byte, 32)
+	c1 := make([]byte, p.CiphertextSize())
+
+	for range n {
+		s.Read(seed)
+		ek, dk, err := p.KeySeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.Write(ek)
+
+		s.Read(m)
+		K, c, err := p.EncapsDerand(ek, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.Write(c)
+		o.Write(K)
+
+		KK, err := p.Decaps(dk, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(KK, K) {
+			t.Errorf("K: got %x, expected %x", KK, K)
+		}
+
+		s.Read(c1)
+		K1, err := p.Decaps(dk, c1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.Write(K1)
+	}
+
+	sum := make([]byte, 32)
+	o.Read(sum)
+	if got := hex.EncodeToString(sum); got != expected {
+		t.Errorf("got %s, expected %s", got, expected)
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
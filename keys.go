@@ -0,0 +1,190 @@
+package mlkem
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"runtime"
+
+	"github.com/AlexanderYastrebov/mlkem/internal"
+)
+
+// Encapsulator is a parsed encapsulation key.
+// It is validated once and caches t̂, ρ, H(ek) and the matrix Â
+// so that repeated encapsulations to the same key do not recompute them.
+type Encapsulator struct {
+	p  ParameterSet
+	ek *internal.EncapsulationKey
+}
+
+// NewEncapsulator validates and parses the encapsulation key.
+func (p ParameterSet) NewEncapsulator(ek EncapsulationKey) (*Encapsulator, error) {
+	if err := p.checkEncapsulationKey("NewEncapsulator", ek); err != nil {
+		return nil, err
+	}
+	return &Encapsulator{p: p, ek: internal.NewEncapsulationKey(ek, p.k())}, nil
+}
+
+// ParameterSet returns the parameter set of the key.
+func (e *Encapsulator) ParameterSet() ParameterSet {
+	return e.p
+}
+
+// Bytes returns the encapsulation key in its encoded form.
+func (e *Encapsulator) Bytes() EncapsulationKey {
+	return e.ek.Bytes()
+}
+
+// Encapsulate generates randomness internally and outputs a shared key and a ciphertext.
+func (e *Encapsulator) Encapsulate() (SharedKey, Ciphertext) {
+	return e.EncapsulateTo(nil, nil)
+}
+
+// EncapsulateTo is like [Encapsulator.Encapsulate] but appends the shared key to dstK
+// and the ciphertext to dstC and returns the extended buffers.
+// It does not allocate if the buffers have enough capacity.
+func (e *Encapsulator) EncapsulateTo(dstK, dstC []byte) (SharedKey, Ciphertext) {
+	var m [32]byte
+	defer clear(m[:])
+	rand.Read(m[:]) // crypto/rand.Read never fails
+	return e.encapsulate(dstK, dstC, m[:])
+}
+
+// EncapsulateRand is like [Encapsulator.Encapsulate] but reads randomness from rand.
+// It returns an error if rand fails or does not provide enough bytes.
+func (e *Encapsulator) EncapsulateRand(rand io.Reader) (SharedKey, Ciphertext, error) {
+	var m [32]byte
+	defer clear(m[:])
+	if _, err := io.ReadFull(rand, m[:]); err != nil {
+		return nil, nil, e.p.entropyError("EncapsulateRand", err)
+	}
+	K, c := e.encapsulate(nil, nil, m[:])
+	return K, c, nil
+}
+
+func (e *Encapsulator) encapsulate(dstK, dstC, m []byte) (SharedKey, Ciphertext) {
+	K, KOut := sliceForAppend(dstK, SharedKeySize)
+	c, cOut := sliceForAppend(dstC, e.p.CiphertextSize())
+	e.ek.Encaps(KOut, cOut, m, e.p.eta1(), e.p.eta2(), e.p.du(), e.p.dv())
+	return K, c
+}
+
+// Decapsulator is a parsed decapsulation key.
+// It caches ŝ, the embedded encapsulation key and the matrix Â
+// so that repeated decapsulations with the same key do not recompute them.
+type Decapsulator struct {
+	p         ParameterSet
+	dk        *internal.DecapsulationKey
+	seed      []byte // nil if parsed from the expanded form
+	destroyed bool
+	mem       *lockedMemory // non-nil if dk and seed are in locked memory, see locked.go
+}
+
+// GenerateDecapsulator generates a new decapsulation key.
+func (p ParameterSet) GenerateDecapsulator() (*Decapsulator, error) {
+	return p.GenerateDecapsulatorRand(rand.Reader)
+}
+
+// GenerateDecapsulatorRand is like [ParameterSet.GenerateDecapsulator] but reads randomness from rand.
+// It returns an error if rand fails or does not provide enough bytes.
+func (p ParameterSet) GenerateDecapsulatorRand(rand io.Reader) (*Decapsulator, error) {
+	if err := p.checkValid("GenerateDecapsulatorRand"); err != nil {
+		return nil, err
+	}
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	if _, err := io.ReadFull(rand, dz[:]); err != nil {
+		return nil, p.entropyError("GenerateDecapsulatorRand", err)
+	}
+	return p.NewDecapsulatorFromSeed(dz[:])
+}
+
+// NewDecapsulatorFromSeed derives a decapsulation key from 64-byte d‖z seed.
+func (p ParameterSet) NewDecapsulatorFromSeed(seed []byte) (*Decapsulator, error) {
+	if err := p.checkValid("NewDecapsulatorFromSeed"); err != nil {
+		return nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, p.newError("NewDecapsulatorFromSeed", "seed", ErrInvalidLength)
+	}
+	seed = bytes.Clone(seed)
+	d, z := seed[:32], seed[32:]
+	return &Decapsulator{p: p, dk: internal.KeyGen(d, z, p.k(), p.eta1()), seed: seed}, nil
+}
+
+// NewDecapsulator validates and parses the decapsulation key.
+func (p ParameterSet) NewDecapsulator(dk DecapsulationKey) (*Decapsulator, error) {
+	if err := p.checkDecapsulationKey("NewDecapsulator", dk); err != nil {
+		return nil, err
+	}
+	return &Decapsulator{p: p, dk: internal.NewDecapsulationKey(dk, p.k())}, nil
+}
+
+// ParameterSet returns the parameter set of the key.
+func (d *Decapsulator) ParameterSet() ParameterSet {
+	return d.p
+}
+
+// Bytes returns the decapsulation key in its encoded form.
+// It returns nil after [Decapsulator.Destroy].
+func (d *Decapsulator) Bytes() DecapsulationKey {
+	if d.destroyed {
+		return nil
+	}
+	dk := d.dk.Bytes()
+	runtime.KeepAlive(d)
+	return dk
+}
+
+// Seed returns the 64-byte d‖z seed of the key.
+// It returns false if the key was parsed from the expanded form,
+// which does not contain d and can not be converted back to a seed,
+// or after [Decapsulator.Destroy].
+func (d *Decapsulator) Seed() ([]byte, bool) {
+	if d.seed == nil || d.destroyed {
+		return nil, false
+	}
+	seed := bytes.Clone(d.seed)
+	runtime.KeepAlive(d)
+	return seed, true
+}
+
+// EncapsulationKey returns the public encapsulation key corresponding to the decapsulation key.
+func (d *Decapsulator) EncapsulationKey() *Encapsulator {
+	if d.dk == nil {
+		return nil // destroyed before expansion, see [SeedDecapsulator.Destroy]
+	}
+	ek := d.dk.EncapsulationKey()
+	if d.mem != nil {
+		// The encapsulation key must not point into the locked memory of d.
+		c := *ek
+		ek = &c
+		runtime.KeepAlive(d)
+	}
+	return &Encapsulator{p: d.p, ek: ek}
+}
+
+// Decapsulate accepts a ciphertext and outputs a shared key.
+func (d *Decapsulator) Decapsulate(c Ciphertext) (SharedKey, error) {
+	return d.decapsulateTo("Decapsulate", nil, c)
+}
+
+// DecapsulateTo is like [Decapsulator.Decapsulate] but appends the shared key to dst
+// and returns the extended buffer.
+// It does not allocate if the buffer has enough capacity.
+func (d *Decapsulator) DecapsulateTo(dst []byte, c Ciphertext) (SharedKey, error) {
+	return d.decapsulateTo("DecapsulateTo", dst, c)
+}
+
+func (d *Decapsulator) decapsulateTo(op string, dst []byte, c Ciphertext) (SharedKey, error) {
+	if d.destroyed {
+		return nil, d.p.newError(op, "dk", ErrKeyDestroyed)
+	}
+	if err := d.p.checkCiphertext(op, c); err != nil {
+		return nil, err
+	}
+	K, KOut := sliceForAppend(dst, SharedKeySize)
+	d.dk.Decaps(KOut, c, d.p.eta1(), d.p.eta2(), d.p.du(), d.p.dv())
+	runtime.KeepAlive(d)
+	return K, nil
+}
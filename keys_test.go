@@ -0,0 +1,156 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestEncapsulator(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			ek, dk := p.KeyGen()
+
+			e, err := p.NewEncapsulator(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ek, e.Bytes()) {
+				t.Error("Bytes() does not round-trip")
+			}
+
+			for range 3 {
+				K1, c := e.Encapsulate()
+				K2, err := p.Decaps(dk, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(K1, K2) {
+					t.Errorf("%x != %x", K1, K2)
+				}
+			}
+
+			bad := bytes.Clone(ek)
+			bad[0] = 0xff
+			bad[1] |= 0x0f
+			if _, err := p.NewEncapsulator(bad); !errors.Is(err, mlkem.ErrEncapsulationKeyModulus) {
+				t.Errorf("expected modulus error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecapsulator(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			d, err := p.GenerateDecapsulator()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ek := d.EncapsulationKey().Bytes()
+			if err := p.ValidateEncapsulationKey(ek); err != nil {
+				t.Fatal(err)
+			}
+
+			d2, err := p.NewDecapsulator(d.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(d.Bytes(), d2.Bytes()) {
+				t.Error("Bytes() does not round-trip")
+			}
+
+			for range 3 {
+				K1, c, err := p.Encaps(ek)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, d := range []*mlkem.Decapsulator{d, d2} {
+					K2, err := d.Decapsulate(c)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(K1, K2) {
+						t.Errorf("%x != %x", K1, K2)
+					}
+				}
+			}
+
+			if _, err := d.Decapsulate(make([]byte, 1)); err == nil {
+				t.Error("expected error for short ciphertext")
+			}
+		})
+	}
+}
+
+func TestNewDecapsulatorFromSeed(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			seed := make([]byte, 64)
+			rand.Read(seed)
+
+			ek, dk, err := p.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d, err := p.NewDecapsulatorFromSeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(dk, d.Bytes()) {
+				t.Error("dk mismatch")
+			}
+			if !bytes.Equal(ek, d.EncapsulationKey().Bytes()) {
+				t.Error("ek mismatch")
+			}
+
+			if _, err := p.NewDecapsulatorFromSeed(seed[1:]); err == nil {
+				t.Error("expected error for short seed")
+			}
+		})
+	}
+}
+
+func TestEncapsulatorDecapsulatorAllocs(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			if raceEnabled {
+				t.Skip("race detector allocates")
+			}
+			d, err := p.GenerateDecapsulator()
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := d.EncapsulationKey()
+			K1 := make([]byte, 0, p.SharedKeySize())
+			K2 := make([]byte, 0, p.SharedKeySize())
+			c := make([]byte, 0, p.CiphertextSize())
+			avg := testing.AllocsPerRun(10, func() {
+				K1, c := e.EncapsulateTo(K1, c)
+				K2, err := d.DecapsulateTo(K2, c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(K1, K2) {
+					t.Fatalf("%x != %x", K1, K2)
+				}
+			})
+			if avg > 0 {
+				t.Errorf("Non-zero allocs: %f", avg)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package mlkem
+
+import (
+	"crypto/rand"
+	"fmt"
+	"runtime"
+
+	"github.com/AlexanderYastrebov/mlkem/internal"
+)
+
+// A [Decapsulator] or a [SeedDecapsulator] can be allocated in locked memory outside of the Go heap.
+// On Linux the memory is locked into RAM with mlock so that it is never swapped out,
+// excluded from core dumps with MADV_DONTDUMP and surrounded by inaccessible guard pages.
+// It holds the seed and the cached ŝ, z and embedded encapsulation key.
+// A locked SeedDecapsulator reserves the memory for the expanded key up front
+// and expands into it on first use.
+// On other platforms the key is allocated on the Go heap, see [Decapsulator.Locked].
+//
+// The memory is zeroed and released once the key becomes unreachable,
+// use Destroy to zero the secrets earlier.
+// The keys returned by the Bytes and Seed methods are copies on the Go heap.
+// To use a locked key with the [crypto/mlkem] compatible types, create it from a seed
+// and wrap it with e.g. [NewDecapsulationKey768FromDecapsulator].
+
+// lockedKey is the part of a [Decapsulator] allocated in locked memory.
+// It must not contain Go pointers as the garbage collector does not scan locked memory.
+type lockedKey struct {
+	dk   internal.DecapsulationKey
+	seed [SeedSize]byte
+}
+
+// lockedMemory owns a lockedKey and releases it once it becomes unreachable.
+// The Decapsulator and the SeedDecapsulator using the key keep it reachable.
+type lockedMemory struct {
+	key    *lockedKey
+	locked bool // false if allocated on the Go heap
+}
+
+func (p ParameterSet) allocLocked(op string) (*lockedMemory, error) {
+	lk, free, err := allocLockedKey()
+	if err != nil {
+		return nil, p.newError(op, "", fmt.Errorf("%w: %w", ErrLockedMemory, err))
+	}
+	m := &lockedMemory{key: lk, locked: free != nil}
+	if free != nil {
+		runtime.AddCleanup(m, func(free func()) { free() }, free)
+	}
+	return m, nil
+}
+
+// expandLocked derives the key from the seed stored in m.
+func (p ParameterSet) expandLocked(m *lockedMemory) *Decapsulator {
+	seed := m.key.seed[:]
+	m.key.dk.Generate(seed[:32], seed[32:], p.k(), p.eta1())
+	return &Decapsulator{p: p, dk: &m.key.dk, seed: seed, mem: m}
+}
+
+// GenerateLockedDecapsulator is like [ParameterSet.GenerateDecapsulator]
+// but allocates the key in locked memory.
+func (p ParameterSet) GenerateLockedDecapsulator() (*Decapsulator, error) {
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	rand.Read(dz[:]) // crypto/rand.Read never fails
+	return p.newLockedDecapsulatorFromSeed("GenerateLockedDecapsulator", dz[:])
+}
+
+// NewLockedDecapsulatorFromSeed is like [ParameterSet.NewDecapsulatorFromSeed]
+// but allocates the key in locked memory.
+func (p ParameterSet) NewLockedDecapsulatorFromSeed(seed []byte) (*Decapsulator, error) {
+	return p.newLockedDecapsulatorFromSeed("NewLockedDecapsulatorFromSeed", seed)
+}
+
+func (p ParameterSet) newLockedDecapsulatorFromSeed(op string, seed []byte) (*Decapsulator, error) {
+	if err := p.checkValid(op); err != nil {
+		return nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, p.newError(op, "seed", ErrInvalidLength)
+	}
+	m, err := p.allocLocked(op)
+	if err != nil {
+		return nil, err
+	}
+	copy(m.key.seed[:], seed)
+	return p.expandLocked(m), nil
+}
+
+// NewLockedDecapsulator is like [ParameterSet.NewDecapsulator]
+// but allocates the key in locked memory.
+func (p ParameterSet) NewLockedDecapsulator(dk DecapsulationKey) (*Decapsulator, error) {
+	if err := p.checkDecapsulationKey("NewLockedDecapsulator", dk); err != nil {
+		return nil, err
+	}
+	m, err := p.allocLocked("NewLockedDecapsulator")
+	if err != nil {
+		return nil, err
+	}
+	m.key.dk.Decode(dk, p.k())
+	return &Decapsulator{p: p, dk: &m.key.dk, mem: m}, nil
+}
+
+// GenerateLockedSeedDecapsulator is like [ParameterSet.GenerateSeedDecapsulator]
+// but allocates the seed and the expanded key in locked memory.
+func (p ParameterSet) GenerateLockedSeedDecapsulator() (*SeedDecapsulator, error) {
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	rand.Read(dz[:]) // crypto/rand.Read never fails
+	return p.newLockedSeedDecapsulator("GenerateLockedSeedDecapsulator", dz[:])
+}
+
+// NewLockedSeedDecapsulator is like [ParameterSet.NewSeedDecapsulator]
+// but allocates the seed and the expanded key in locked memory.
+func (p ParameterSet) NewLockedSeedDecapsulator(seed []byte) (*SeedDecapsulator, error) {
+	return p.newLockedSeedDecapsulator("NewLockedSeedDecapsulator", seed)
+}
+
+func (p ParameterSet) newLockedSeedDecapsulator(op string, seed []byte) (*SeedDecapsulator, error) {
+	if err := p.checkValid(op); err != nil {
+		return nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, p.newError(op, "seed", ErrInvalidLength)
+	}
+	m, err := p.allocLocked(op)
+	if err != nil {
+		return nil, err
+	}
+	copy(m.key.seed[:], seed)
+	return &SeedDecapsulator{p: p, seed: m.key.seed[:], mem: m}, nil
+}
+
+// Locked reports whether the key is allocated in locked memory.
+// It is false for keys created by the other constructors
+// and on platforms where locked memory is not supported.
+func (d *Decapsulator) Locked() bool {
+	return d.mem != nil && d.mem.locked
+}
+
+// Locked reports whether the key is allocated in locked memory, see [Decapsulator.Locked].
+func (s *SeedDecapsulator) Locked() bool {
+	return s.mem != nil && s.mem.locked
+}
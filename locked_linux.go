@@ -0,0 +1,45 @@
+//go:build linux
+
+package mlkem
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// madvDontDump is MADV_DONTDUMP, which the syscall package does not define on all architectures.
+const madvDontDump = 0x10
+
+// allocLockedKey maps a zero lockedKey, rounded up to whole pages, between two guard pages.
+// The returned function zeroes and unmaps it.
+func allocLockedKey() (*lockedKey, func(), error) {
+	page := os.Getpagesize()
+	size := (int(unsafe.Sizeof(lockedKey{})) + page - 1) / page * page
+	mapping, err := syscall.Mmap(-1, 0, page+size+page, syscall.PROT_NONE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := mapping[page : page+size]
+	if err := lock(b); err != nil {
+		syscall.Munmap(mapping)
+		return nil, nil, err
+	}
+	free := func() {
+		clear(b)
+		syscall.Munmap(mapping) // also unlocks
+	}
+	return (*lockedKey)(unsafe.Pointer(&b[0])), free, nil
+}
+
+// lock makes b accessible, locks it into RAM and excludes it from core dumps.
+// The guard pages around b stay inaccessible.
+func lock(b []byte) error {
+	if err := syscall.Mprotect(b, syscall.PROT_READ|syscall.PROT_WRITE); err != nil {
+		return err
+	}
+	if err := syscall.Mlock(b); err != nil {
+		return err
+	}
+	return syscall.Madvise(b, madvDontDump)
+}
@@ -0,0 +1,51 @@
+package mlkem_test
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+// smaps returns the number of kilobytes of locked and of non-dumpable memory
+// in the mappings of the process.
+func smaps(t *testing.T) (locked, dontDump int) {
+	b, err := os.ReadFile("/proc/self/smaps")
+	if err != nil {
+		t.Skip(err)
+	}
+	size := 0
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		switch {
+		case len(f) == 3 && f[0] == "Size:":
+			size, _ = strconv.Atoi(f[1])
+		case len(f) == 3 && f[0] == "Locked:":
+			n, _ := strconv.Atoi(f[1])
+			locked += n
+		case len(f) > 0 && f[0] == "VmFlags:" && strings.Contains(s.Text(), " dd"):
+			dontDump += size
+		}
+	}
+	return locked, dontDump
+}
+
+func TestLockedMemoryLinux(t *testing.T) {
+	locked0, dontDump0 := smaps(t)
+	d, err := mlkem.MLKEM_1024.GenerateLockedDecapsulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked1, dontDump1 := smaps(t)
+	// ML-KEM-1024 caches ŝ, t̂ and Â, 24 polynomials of 512 bytes.
+	if locked1-locked0 < 12 || dontDump1-dontDump0 < 12 {
+		t.Errorf("locked %d KiB, excluded from core dumps %d KiB", locked1-locked0, dontDump1-dontDump0)
+	}
+	runtime.KeepAlive(d)
+}
@@ -0,0 +1,164 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestLockedDecapsulator(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			seed := make([]byte, mlkem.SeedSize)
+			rand.Read(seed)
+			ek, dk, err := p.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d1, err := p.NewLockedDecapsulatorFromSeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d2, err := p.NewLockedDecapsulator(dk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			d3, err := p.GenerateLockedDecapsulator()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range []*mlkem.Decapsulator{d1, d2, d3} {
+				if d.Locked() != (runtime.GOOS == "linux") {
+					t.Errorf("Locked() = %v on %s", d.Locked(), runtime.GOOS)
+				}
+			}
+
+			if !bytes.Equal(dk, d1.Bytes()) || !bytes.Equal(dk, d2.Bytes()) {
+				t.Error("dk mismatch")
+			}
+			if s, ok := d1.Seed(); !ok || !bytes.Equal(s, seed) {
+				t.Error("seed mismatch")
+			}
+			if _, ok := d2.Seed(); ok {
+				t.Error("expanded key has a seed")
+			}
+
+			for _, d := range []*mlkem.Decapsulator{d1, d2} {
+				K1, c, err := p.Encaps(ek)
+				if err != nil {
+					t.Fatal(err)
+				}
+				K2, err := d.Decapsulate(c)
+				if err != nil || !bytes.Equal(K1, K2) {
+					t.Errorf("decapsulation mismatch: %v", err)
+				}
+			}
+
+			// The encapsulation key outlives the locked memory of d3.
+			e := d3.EncapsulationKey()
+			ek3 := e.Bytes()
+			d3 = nil
+			runtime.GC()
+			runtime.GC()
+			if !bytes.Equal(ek3, e.Bytes()) {
+				t.Error("encapsulation key changed after the decapsulation key was released")
+			}
+			e.Encapsulate()
+
+			d1.Destroy()
+			if _, err := d1.Decapsulate(make([]byte, p.CiphertextSize())); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+				t.Errorf("expected destroyed error, got: %v", err)
+			}
+
+			if _, err := p.NewLockedDecapsulatorFromSeed(seed[1:]); !errors.Is(err, mlkem.ErrInvalidLength) {
+				t.Errorf("expected length error, got: %v", err)
+			}
+			bad := bytes.Clone(dk)
+			bad[len(bad)-33] ^= 1 // h
+			if _, err := p.NewLockedDecapsulator(bad); !errors.Is(err, mlkem.ErrDecapsulationKeyHash) {
+				t.Errorf("expected hash error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestLockedStd(t *testing.T) {
+	d, err := mlkem.MLKEM_768.GenerateLockedDecapsulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dk, err := mlkem.NewDecapsulationKey768FromDecapsulator(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	K1, c := dk.EncapsulationKey().Encapsulate()
+	K2, err := dk.Decapsulate(c)
+	if err != nil || !bytes.Equal(K1, K2) {
+		t.Errorf("decapsulation mismatch: %v", err)
+	}
+}
+
+func TestLockedSeedDecapsulator(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			seed := make([]byte, mlkem.SeedSize)
+			rand.Read(seed)
+			ek, dk, err := p.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := p.NewLockedSeedDecapsulator(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Locked() != (runtime.GOOS == "linux") {
+				t.Errorf("Locked() = %v on %s", s.Locked(), runtime.GOOS)
+			}
+			if !bytes.Equal(s.Seed(), seed) {
+				t.Error("seed mismatch")
+			}
+			d := s.Decapsulator()
+			if d.Locked() != s.Locked() {
+				t.Error("expanded key is not locked")
+			}
+			if !bytes.Equal(dk, s.Bytes()) {
+				t.Error("dk mismatch")
+			}
+
+			// The expanded key keeps the locked memory alive.
+			s = nil
+			runtime.GC()
+			runtime.GC()
+			K1, c, err := p.Encaps(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if K2, err := d.Decapsulate(c); err != nil || !bytes.Equal(K1, K2) {
+				t.Errorf("decapsulation mismatch: %v", err)
+			}
+
+			s, err = p.GenerateLockedSeedDecapsulator()
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Destroy()
+			if _, err := s.Decapsulate(c); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+				t.Errorf("expected destroyed error, got: %v", err)
+			}
+
+			if _, err := p.NewLockedSeedDecapsulator(seed[1:]); !errors.Is(err, mlkem.ErrInvalidLength) {
+				t.Errorf("expected length error, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package mlkem
+
+import (
+	"crypto/rand"
+
+	"github.com/AlexanderYastrebov/mlkem/internal"
+)
+
+// LowMemory runs the algorithms of a parameter set without materializing the k×k matrix Â,
+// which takes up to 8 KiB for ML-KEM-1024. Each entry of Â is sampled when it is needed
+// and the vectors t̂ and ŝ are decoded from the keys one polynomial at a time.
+//
+// The outputs are identical to those of the corresponding [ParameterSet] methods.
+// Since nothing is cached, Â is sampled anew by every call;
+// use [Encapsulator] and [Decapsulator] when memory is not constrained.
+type LowMemory struct {
+	p ParameterSet
+}
+
+// LowMemory returns the low-memory mode of p.
+func (p ParameterSet) LowMemory() LowMemory {
+	p.params() // panic early on invalid parameter set
+	return LowMemory{p: p}
+}
+
+// ParameterSet returns the parameter set.
+func (l LowMemory) ParameterSet() ParameterSet {
+	return l.p
+}
+
+// KeyGen is like [ParameterSet.KeyGen].
+func (l LowMemory) KeyGen() (EncapsulationKey, DecapsulationKey) {
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	rand.Read(dz[:]) // crypto/rand.Read never fails
+	return l.keyGen(dz[:32], dz[32:])
+}
+
+// KeySeed is like [ParameterSet.KeySeed].
+func (l LowMemory) KeySeed(seed []byte) (EncapsulationKey, DecapsulationKey, error) {
+	if err := l.p.checkValid("KeySeed"); err != nil {
+		return nil, nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, nil, l.p.newError("KeySeed", "seed", ErrInvalidLength)
+	}
+	ek, dk := l.keyGen(seed[:32], seed[32:])
+	return ek, dk, nil
+}
+
+func (l LowMemory) keyGen(d, z []byte) (EncapsulationKey, DecapsulationKey) {
+	p := l.p
+	ek, dk := make([]byte, p.EncapsulationKeySize()), make([]byte, p.DecapsulationKeySize())
+	internal.KeyGenStreaming_internal(ek, dk, d, z, p.k(), p.eta1())
+	return ek, dk
+}
+
+// Encaps is like [ParameterSet.Encaps].
+func (l LowMemory) Encaps(ek EncapsulationKey) (SharedKey, Ciphertext, error) {
+	if err := l.p.checkEncapsulationKey("Encaps", ek); err != nil {
+		return nil, nil, err
+	}
+	var m [32]byte
+	defer clear(m[:])
+	rand.Read(m[:]) // crypto/rand.Read never fails
+	K, c := l.encaps(ek, m[:])
+	return K, c, nil
+}
+
+// EncapsDerand is like [ParameterSet.EncapsDerand].
+func (l LowMemory) EncapsDerand(ek EncapsulationKey, m []byte) (SharedKey, Ciphertext, error) {
+	if err := l.p.checkEncapsulationKey("EncapsDerand", ek); err != nil {
+		return nil, nil, err
+	}
+	if len(m) != 32 {
+		return nil, nil, l.p.newError("EncapsDerand", "m", ErrInvalidLength)
+	}
+	K, c := l.encaps(ek, m)
+	return K, c, nil
+}
+
+func (l LowMemory) encaps(ek EncapsulationKey, m []byte) (SharedKey, Ciphertext) {
+	p := l.p
+	K, c := make([]byte, SharedKeySize), make([]byte, p.CiphertextSize())
+	internal.EncapsStreaming_internal(K, c, ek, m, p.k(), p.eta1(), p.eta2(), p.du(), p.dv())
+	return K, c
+}
+
+// Decaps is like [ParameterSet.Decaps].
+func (l LowMemory) Decaps(dk DecapsulationKey, c Ciphertext) (SharedKey, error) {
+	p := l.p
+	if err := p.checkCiphertext("Decaps", c); err != nil {
+		return nil, err
+	}
+	if err := p.checkDecapsulationKey("Decaps", dk); err != nil {
+		return nil, err
+	}
+	K := make([]byte, SharedKeySize)
+	internal.DecapsStreaming_internal(K, dk, c, p.k(), p.eta1(), p.eta2(), p.du(), p.dv())
+	return K, nil
+}
@@ -0,0 +1,71 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestLowMemory(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			l := p.LowMemory()
+
+			seed := make([]byte, mlkem.SeedSize)
+			rand.Read(seed)
+			ek1, dk1, err := p.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ek2, dk2, err := l.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ek1, ek2) || !bytes.Equal(dk1, dk2) {
+				t.Fatal("key generation mismatch")
+			}
+
+			m := make([]byte, 32)
+			rand.Read(m)
+			K1, c1, err := p.EncapsDerand(ek1, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			K2, c2, err := l.EncapsDerand(ek1, m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(K1, K2) || !bytes.Equal(c1, c2) {
+				t.Fatal("encapsulation mismatch")
+			}
+
+			K3, err := l.Decaps(dk1, c1)
+			if err != nil || !bytes.Equal(K3, K1) {
+				t.Fatalf("decapsulation mismatch: %v", err)
+			}
+			c1[0] ^= 1
+			K4, _ := p.Decaps(dk1, c1)
+			if K5, err := l.Decaps(dk1, c1); err != nil || !bytes.Equal(K4, K5) {
+				t.Fatalf("implicit rejection mismatch: %v", err)
+			}
+
+			ek, dk := l.KeyGen()
+			K6, c, err := l.Encaps(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if K7, err := p.Decaps(dk, c); err != nil || !bytes.Equal(K6, K7) {
+				t.Fatalf("round trip failed: %v", err)
+			}
+
+			if _, err := l.Decaps(dk, c[1:]); !errors.Is(err, mlkem.ErrInvalidLength) {
+				t.Errorf("expected length error, got: %v", err)
+			}
+		})
+	}
+}
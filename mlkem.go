@@ -1,25 +1,76 @@
 // Package mlkem implements Module-Lattice-Based Key-Encapsulation Mechanism ([ML-KEM]).
 //
+// Parameter sets can be looked up by name, object identifier, TLS NamedGroup
+// and HPKE KEM identifier. COSE algorithm identifiers are not provided
+// because IANA has not assigned any to ML-KEM yet.
+//
 // [ML-KEM]: https://nvlpubs.nist.gov/nistpubs/fips/nist.fips.203.pdf
 package mlkem
 
 import (
+	"bytes"
 	"crypto/rand"
-	"errors"
+	"io"
+	"strconv"
 
 	"github.com/AlexanderYastrebov/mlkem/internal"
 )
 
-type ParameterSet struct {
+// ParameterSet identifies one of the ML-KEM parameter sets defined in FIPS 203 §8.
+// Parameter sets are constants and can not be modified.
+// The zero value is not a valid parameter set: methods that return an error
+// report [ErrInvalidParameterSet] for it, the others panic.
+type ParameterSet uint8
+
+const (
+	MLKEM_512 ParameterSet = iota + 1
+	MLKEM_768
+	MLKEM_1024
+)
+
+type parameters struct {
 	name                  string
 	k, eta1, eta2, du, dv int
+	category              int
+	oid                   []int
+	tlsGroup, hpkeKEM     uint16
 }
 
-var (
-	MLKEM_512  ParameterSet = ParameterSet{name: "ML-KEM-512", k: 2, eta1: 3, eta2: 2, du: 10, dv: 4}
-	MLKEM_768  ParameterSet = ParameterSet{name: "ML-KEM-768", k: 3, eta1: 2, eta2: 2, du: 10, dv: 4}
-	MLKEM_1024 ParameterSet = ParameterSet{name: "ML-KEM-1024", k: 4, eta1: 2, eta2: 2, du: 11, dv: 5}
-)
+var parameterSets = [...]parameters{
+	MLKEM_512: {
+		name: "ML-KEM-512", k: 2, eta1: 3, eta2: 2, du: 10, dv: 4, category: 1,
+		oid: []int{2, 16, 840, 1, 101, 3, 4, 4, 1}, tlsGroup: 0x0200, hpkeKEM: 0x0040,
+	},
+	MLKEM_768: {
+		name: "ML-KEM-768", k: 3, eta1: 2, eta2: 2, du: 10, dv: 4, category: 3,
+		oid: []int{2, 16, 840, 1, 101, 3, 4, 4, 2}, tlsGroup: 0x0201, hpkeKEM: 0x0041,
+	},
+	MLKEM_1024: {
+		name: "ML-KEM-1024", k: 4, eta1: 2, eta2: 2, du: 11, dv: 5, category: 5,
+		oid: []int{2, 16, 840, 1, 101, 3, 4, 4, 3}, tlsGroup: 0x0202, hpkeKEM: 0x0042,
+	},
+}
+
+func (p ParameterSet) params() *parameters {
+	if !p.Valid() {
+		panic("mlkem: invalid parameter set " + strconv.Itoa(int(p)))
+	}
+	return &parameterSets[p]
+}
+
+// checkValid returns an error for parameter sets that would make params panic.
+func (p ParameterSet) checkValid(op string) error {
+	if !p.Valid() {
+		return p.newError(op, "", ErrInvalidParameterSet)
+	}
+	return nil
+}
+
+func (p ParameterSet) k() int    { return p.params().k }
+func (p ParameterSet) eta1() int { return p.params().eta1 }
+func (p ParameterSet) eta2() int { return p.params().eta2 }
+func (p ParameterSet) du() int   { return p.params().du }
+func (p ParameterSet) dv() int   { return p.params().dv }
 
 type (
 	EncapsulationKey []byte
@@ -29,59 +80,205 @@ type (
 	Ciphertext       []byte
 )
 
-var (
-	errInvalidKey        = errors.New("invalid key")
-	errInvalidCiphertext = errors.New("invalid ciphertext")
-)
-
 // The key generation algorithm accepts no input,
 // generates randomness internally, and produces an encapsulation key and a decapsulation key.
 // While the encapsulation key can be made public, the decapsulation key shall remain private.
-func (p *ParameterSet) KeyGen() (EncapsulationKey, DecapsulationKey) {
-	var d, z [32]byte
-	rand.Read(d[:])
-	rand.Read(z[:])
-	ek, dk := internal.KeyGen_internal(d[:], z[:], p.k, p.eta1)
-	return ek, dk
+func (p ParameterSet) KeyGen() (EncapsulationKey, DecapsulationKey) {
+	return p.KeyGenTo(nil, nil)
+}
+
+// KeyGenTo is like [ParameterSet.KeyGen] but appends the encapsulation key to dstEK
+// and the decapsulation key to dstDK and returns the extended buffers.
+// It does not allocate if the buffers have enough capacity.
+func (p ParameterSet) KeyGenTo(dstEK, dstDK []byte) (EncapsulationKey, DecapsulationKey) {
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	rand.Read(dz[:]) // crypto/rand.Read never fails
+	return p.keyGen(dstEK, dstDK, dz[:32], dz[32:])
+}
+
+// KeyGenRand is like [ParameterSet.KeyGen] but reads randomness from rand.
+// It returns an error if rand fails or does not provide enough bytes.
+func (p ParameterSet) KeyGenRand(rand io.Reader) (EncapsulationKey, DecapsulationKey, error) {
+	if err := p.checkValid("KeyGenRand"); err != nil {
+		return nil, nil, err
+	}
+	var dz [SeedSize]byte
+	defer clear(dz[:])
+	if _, err := io.ReadFull(rand, dz[:]); err != nil {
+		return nil, nil, p.entropyError("KeyGenRand", err)
+	}
+	ek, dk := p.keyGen(nil, nil, dz[:32], dz[32:])
+	return ek, dk, nil
 }
 
 // KeySeed produces an encapsulation key and a decapsulation key from 64-byte d‖z seed.
-func (p *ParameterSet) KeySeed(seed []byte) (EncapsulationKey, DecapsulationKey) {
-	if len(seed) != 64 {
-		panic("invalid seed")
+func (p ParameterSet) KeySeed(seed []byte) (EncapsulationKey, DecapsulationKey, error) {
+	if err := p.checkValid("KeySeed"); err != nil {
+		return nil, nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, nil, p.newError("KeySeed", "seed", ErrInvalidLength)
 	}
-	d, z := seed[:32], seed[32:]
-	ek, dk := internal.KeyGen_internal(d[:], z[:], p.k, p.eta1)
+	ek, dk := p.keyGen(nil, nil, seed[:32], seed[32:])
+	return ek, dk, nil
+}
+
+func (p ParameterSet) keyGen(dstEK, dstDK, d, z []byte) (EncapsulationKey, DecapsulationKey) {
+	ek, ekOut := sliceForAppend(dstEK, p.EncapsulationKeySize())
+	dk, dkOut := sliceForAppend(dstDK, p.DecapsulationKeySize())
+	internal.KeyGen_internal(ekOut, dkOut, d, z, p.k(), p.eta1())
 	return ek, dk
 }
 
 // The encapsulation algorithm accepts an encapsulation key as input,
 // generates randomness internally, and outputs a ciphertext and a shared key.
-func (p *ParameterSet) Encaps(ek EncapsulationKey) (SharedKey, Ciphertext, error) {
-	if len(ek) != 384*p.k+32 {
-		return nil, nil, errInvalidKey
+func (p ParameterSet) Encaps(ek EncapsulationKey) (SharedKey, Ciphertext, error) {
+	return p.encapsTo("Encaps", nil, nil, ek)
+}
+
+// EncapsTo is like [ParameterSet.Encaps] but appends the shared key to dstK
+// and the ciphertext to dstC and returns the extended buffers.
+// It does not allocate if the buffers have enough capacity.
+func (p ParameterSet) EncapsTo(dstK, dstC []byte, ek EncapsulationKey) (SharedKey, Ciphertext, error) {
+	return p.encapsTo("EncapsTo", dstK, dstC, ek)
+}
+
+func (p ParameterSet) encapsTo(op string, dstK, dstC []byte, ek EncapsulationKey) (SharedKey, Ciphertext, error) {
+	if err := p.checkEncapsulationKey(op, ek); err != nil {
+		return nil, nil, err
 	}
-	// TODO: Modulus check
 	var m [32]byte
-	rand.Read(m[:])
-	K, c := internal.Encaps_internal(ek, m[:], p.k, p.eta1, p.eta2, p.du, p.dv)
+	defer clear(m[:])
+	rand.Read(m[:]) // crypto/rand.Read never fails
+	K, c := p.encaps(dstK, dstC, ek, m[:])
 	return K, c, nil
 }
 
+// EncapsRand is like [ParameterSet.Encaps] but reads randomness from rand.
+// It returns an error if rand fails or does not provide enough bytes.
+func (p ParameterSet) EncapsRand(rand io.Reader, ek EncapsulationKey) (SharedKey, Ciphertext, error) {
+	if err := p.checkEncapsulationKey("EncapsRand", ek); err != nil {
+		return nil, nil, err
+	}
+	var m [32]byte
+	defer clear(m[:])
+	if _, err := io.ReadFull(rand, m[:]); err != nil {
+		return nil, nil, p.entropyError("EncapsRand", err)
+	}
+	K, c := p.encaps(nil, nil, ek, m[:])
+	return K, c, nil
+}
+
+func (p ParameterSet) encaps(dstK, dstC []byte, ek EncapsulationKey, m []byte) (SharedKey, Ciphertext) {
+	K, KOut := sliceForAppend(dstK, SharedKeySize)
+	c, cOut := sliceForAppend(dstC, p.CiphertextSize())
+	internal.Encaps_internal(KOut, cOut, ek, m, p.k(), p.eta1(), p.eta2(), p.du(), p.dv())
+	return K, c
+}
+
+// ValidateEncapsulationKey performs the encapsulation key input check (FIPS 203 §7.2):
+// the key must have the expected length and ByteEncode₁₂(ByteDecode₁₂(ek)) must equal ek
+// for every encoded polynomial.
+func (p ParameterSet) ValidateEncapsulationKey(ek EncapsulationKey) error {
+	return p.checkEncapsulationKey("ValidateEncapsulationKey", ek)
+}
+
+func (p ParameterSet) checkEncapsulationKey(op string, ek EncapsulationKey) error {
+	if err := p.checkValid(op); err != nil {
+		return err
+	}
+	if len(ek) != p.EncapsulationKeySize() {
+		return p.newError(op, "ek", ErrInvalidLength)
+	}
+	if !internal.CheckModulus(ek[:384*p.k()]) {
+		return p.newError(op, "ek", ErrEncapsulationKeyModulus)
+	}
+	return nil
+}
+
 // The decapsulation algorithm accepts a decapsulation key and an ML-KEM ciphertext as input,
 // does not use any randomness, and outputs a shared secret.
-func (p *ParameterSet) Decaps(dk DecapsulationKey, c Ciphertext) (SharedKey, error) {
-	if len(c) != 32*(p.du*p.k+p.dv) {
-		return nil, errInvalidCiphertext
+func (p ParameterSet) Decaps(dk DecapsulationKey, c Ciphertext) (SharedKey, error) {
+	return p.decapsTo("Decaps", nil, dk, c)
+}
+
+// DecapsTo is like [ParameterSet.Decaps] but appends the shared key to dst
+// and returns the extended buffer.
+// It does not allocate if the buffer has enough capacity.
+func (p ParameterSet) DecapsTo(dst []byte, dk DecapsulationKey, c Ciphertext) (SharedKey, error) {
+	return p.decapsTo("DecapsTo", dst, dk, c)
+}
+
+func (p ParameterSet) decapsTo(op string, dst []byte, dk DecapsulationKey, c Ciphertext) (SharedKey, error) {
+	if err := p.checkCiphertext(op, c); err != nil {
+		return nil, err
 	}
-	if len(dk) != 768*p.k+96 {
-		return nil, errInvalidKey
+	if err := p.checkDecapsulationKey(op, dk); err != nil {
+		return nil, err
 	}
-	// TODO: Hash check
-	K := internal.Decaps_internal(dk, c, p.k, p.eta1, p.eta2, p.du, p.dv)
+	K, KOut := sliceForAppend(dst, SharedKeySize)
+	internal.Decaps_internal(KOut, dk, c, p.k(), p.eta1(), p.eta2(), p.du(), p.dv())
 	return K, nil
 }
 
-func (p *ParameterSet) String() string {
-	return p.name
+// ValidateDecapsulationKey performs the decapsulation key input check (FIPS 203 §7.3):
+// the key must have the expected length and H(ek) must equal the hash h stored in the key.
+// In addition, both the embedded encapsulation key and the secret vector ŝ
+// must pass the modulus check.
+func (p ParameterSet) ValidateDecapsulationKey(dk DecapsulationKey) error {
+	return p.checkDecapsulationKey("ValidateDecapsulationKey", dk)
+}
+
+func (p ParameterSet) checkDecapsulationKey(op string, dk DecapsulationKey) error {
+	if err := p.checkValid(op); err != nil {
+		return err
+	}
+	if len(dk) != p.DecapsulationKeySize() {
+		return p.newError(op, "dk", ErrInvalidLength)
+	}
+	dkPKE := dk[:384*p.k()]
+	ek := dk[384*p.k() : 768*p.k()+32]
+	h := dk[768*p.k()+32 : 768*p.k()+64]
+	if hash := internal.H(ek); !bytes.Equal(hash[:], h) {
+		return p.newError(op, "dk", ErrDecapsulationKeyHash)
+	}
+	if !internal.CheckModulus(ek[:384*p.k()]) {
+		return p.newError(op, "dk", ErrEncapsulationKeyModulus)
+	}
+	if !internal.CheckModulus(dkPKE) {
+		return p.newError(op, "dk", ErrDecapsulationKeyModulus)
+	}
+	return nil
+}
+
+func (p ParameterSet) checkCiphertext(op string, c Ciphertext) error {
+	if err := p.checkValid(op); err != nil {
+		return err
+	}
+	if len(c) != p.CiphertextSize() {
+		return p.newError(op, "c", ErrInvalidLength)
+	}
+	return nil
+}
+
+func (p ParameterSet) String() string {
+	if !p.Valid() {
+		return "ParameterSet(" + strconv.Itoa(int(p)) + ")"
+	}
+	return p.params().name
+}
+
+// sliceForAppend takes a slice and a requested number of bytes.
+// It returns a slice with the contents of the given slice followed by that many bytes
+// and a second slice that aliases into it and contains only the extra bytes.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
 }
@@ -3,6 +3,10 @@ package mlkem_test
 import (
 	"bytes"
 	stdmlkem "crypto/mlkem"
+	"crypto/rand"
+	"crypto/sha3"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/AlexanderYastrebov/mlkem"
@@ -32,6 +36,70 @@ func TestParameterSet(t *testing.T) {
 	}
 }
 
+func TestAppend(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			prefix := []byte("prefix")
+			ek, dk := p.KeyGenTo(bytes.Clone(prefix), bytes.Clone(prefix))
+			if !bytes.HasPrefix(ek, prefix) || !bytes.HasPrefix(dk, prefix) {
+				t.Fatal("KeyGenTo did not preserve the prefix")
+			}
+			ek, dk = ek[len(prefix):], dk[len(prefix):]
+			if err := p.ValidateDecapsulationKey(dk); err != nil {
+				t.Fatal(err)
+			}
+
+			K1, c, err := p.EncapsTo(bytes.Clone(prefix), bytes.Clone(prefix), ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(K1, prefix) || !bytes.HasPrefix(c, prefix) {
+				t.Fatal("EncapsTo did not preserve the prefix")
+			}
+			K1, c = K1[len(prefix):], c[len(prefix):]
+
+			K2, err := p.DecapsTo(bytes.Clone(prefix), dk, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(K2, append(bytes.Clone(prefix), K1...)) {
+				t.Errorf("%x != %x", K1, K2)
+			}
+		})
+	}
+}
+
+func TestAllocs(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			if raceEnabled {
+				t.Skip("race detector allocates")
+			}
+			ek := make([]byte, 0, p.EncapsulationKeySize())
+			dk := make([]byte, 0, p.DecapsulationKeySize())
+			K := make([]byte, 0, p.SharedKeySize())
+			c := make([]byte, 0, p.CiphertextSize())
+			avg := testing.AllocsPerRun(10, func() {
+				ek, dk := p.KeyGenTo(ek, dk)
+				_, c, err := p.EncapsTo(K, c, ek)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := p.DecapsTo(K, dk, c); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if avg > 0 {
+				t.Errorf("Non-zero allocs: %f", avg)
+			}
+		})
+	}
+}
+
 func TestCompatibility(t *testing.T) {
 	t.Run("KeySeed", func(t *testing.T) {
 		dk, err := stdmlkem.GenerateKey768()
@@ -40,7 +108,10 @@ func TestCompatibility(t *testing.T) {
 		}
 		ek1 := dk.EncapsulationKey().Bytes()
 
-		ek2, _ := mlkem.MLKEM_768.KeySeed(dk.Bytes())
+		ek2, _, err := mlkem.MLKEM_768.KeySeed(dk.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
 
 		if !bytes.Equal(ek1, ek2) {
 			t.Error("ek1 != ek2")
@@ -76,7 +147,10 @@ func TestCompatibility(t *testing.T) {
 		}
 		K1, c := dk.EncapsulationKey().Encapsulate()
 
-		_, dk2 := mlkem.MLKEM_768.KeySeed(dk.Bytes())
+		_, dk2, err := mlkem.MLKEM_768.KeySeed(dk.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
 		K2, err := mlkem.MLKEM_768.Decaps(dk2, c)
 		if err != nil {
 			t.Fatal(err)
@@ -87,3 +161,204 @@ func TestCompatibility(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateEncapsulationKey(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			ek, _ := p.KeyGen()
+			if err := p.ValidateEncapsulationKey(ek); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.ValidateEncapsulationKey(ek[1:]); err == nil {
+				t.Error("expected error for short key")
+			}
+
+			// Set the first 12-bit coefficient to 4095 ≥ q.
+			bad := bytes.Clone(ek)
+			bad[0] = 0xff
+			bad[1] |= 0x0f
+			if err := p.ValidateEncapsulationKey(bad); !errors.Is(err, mlkem.ErrEncapsulationKeyModulus) {
+				t.Errorf("expected modulus error, got: %v", err)
+			}
+			if _, _, err := p.Encaps(bad); !errors.Is(err, mlkem.ErrEncapsulationKeyModulus) {
+				t.Errorf("expected modulus error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateDecapsulationKey(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			ek, dk := p.KeyGen()
+			if err := p.ValidateDecapsulationKey(dk); err != nil {
+				t.Fatal(err)
+			}
+
+			_, c, err := p.Encaps(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.ValidateDecapsulationKey(dk[1:]); err == nil {
+				t.Error("expected error for short key")
+			}
+
+			for _, tc := range []struct {
+				name   string
+				modify func(dk []byte)
+				err    error
+			}{
+				{
+					name: "secret modulus",
+					modify: func(dk []byte) {
+						dk[0] = 0xff
+						dk[1] |= 0x0f
+					},
+					err: mlkem.ErrDecapsulationKeyModulus,
+				},
+				{
+					name: "encapsulation key modulus",
+					modify: func(dk []byte) {
+						// Corrupt the embedded ek and recompute its hash.
+						ek := dk[len(dk)-len(ek)-64 : len(dk)-64]
+						ek[0] = 0xff
+						ek[1] |= 0x0f
+						h := sha3.Sum256(ek)
+						copy(dk[len(dk)-64:], h[:])
+					},
+					err: mlkem.ErrEncapsulationKeyModulus,
+				},
+				{
+					name: "hash",
+					modify: func(dk []byte) {
+						dk[len(dk)-64] ^= 1
+					},
+					err: mlkem.ErrDecapsulationKeyHash,
+				},
+				{
+					name: "embedded encapsulation key",
+					modify: func(dk []byte) {
+						dk[len(dk)-65] ^= 1
+					},
+					err: mlkem.ErrDecapsulationKeyHash,
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					bad := bytes.Clone(dk)
+					tc.modify(bad)
+					if err := p.ValidateDecapsulationKey(bad); !errors.Is(err, tc.err) {
+						t.Errorf("expected %v, got: %v", tc.err, err)
+					}
+					if _, err := p.Decaps(bad, c); !errors.Is(err, tc.err) {
+						t.Errorf("expected %v, got: %v", tc.err, err)
+					}
+				})
+			}
+		})
+	}
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) {
+	return 0, errors.New("entropy source failure")
+}
+
+func TestRand(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			seed := make([]byte, 64)
+			rand.Read(seed)
+
+			ek, dk, err := p.KeyGenRand(bytes.NewReader(seed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ek2, dk2, err := p.KeySeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(ek, ek2) || !bytes.Equal(dk, dk2) {
+				t.Error("KeyGenRand does not match KeySeed")
+			}
+
+			d, err := p.GenerateDecapsulatorRand(bytes.NewReader(seed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(dk, d.Bytes()) {
+				t.Error("GenerateDecapsulatorRand does not match KeySeed")
+			}
+
+			m := make([]byte, 32)
+			rand.Read(m)
+			K1, c1, err := p.EncapsRand(bytes.NewReader(m), ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			K2, c2, err := d.EncapsulationKey().EncapsulateRand(bytes.NewReader(m))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(K1, K2) || !bytes.Equal(c1, c2) {
+				t.Error("EncapsRand is not deterministic")
+			}
+
+			for _, r := range []io.Reader{errorReader{}, bytes.NewReader(seed[:31])} {
+				if _, _, err := p.KeyGenRand(r); err == nil {
+					t.Error("KeyGenRand: expected error")
+				}
+				if _, err := p.GenerateDecapsulatorRand(r); err == nil {
+					t.Error("GenerateDecapsulatorRand: expected error")
+				}
+				if _, _, err := p.EncapsRand(r, ek); err == nil {
+					t.Error("EncapsRand: expected error")
+				}
+				if _, _, err := d.EncapsulationKey().EncapsulateRand(r); err == nil {
+					t.Error("EncapsulateRand: expected error")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkKeyGen(b *testing.B) {
+	for _, p := range mlkem.ParameterSets() {
+		b.Run(p.String(), func(b *testing.B) {
+			for b.Loop() {
+				p.KeyGen()
+			}
+		})
+	}
+}
+
+func BenchmarkEncaps(b *testing.B) {
+	for _, p := range mlkem.ParameterSets() {
+		b.Run(p.String(), func(b *testing.B) {
+			ek, _ := p.KeyGen()
+			for b.Loop() {
+				p.Encaps(ek)
+			}
+		})
+	}
+}
+
+func BenchmarkDecaps(b *testing.B) {
+	for _, p := range mlkem.ParameterSets() {
+		b.Run(p.String(), func(b *testing.B) {
+			ek, dk := p.KeyGen()
+			_, c, _ := p.Encaps(ek)
+			for b.Loop() {
+				p.Decaps(dk, c)
+			}
+		})
+	}
+}
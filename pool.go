@@ -0,0 +1,106 @@
+package mlkem
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// KeyPool keeps pre-generated key pairs ready so that generating an ephemeral key pair
+// does not have to wait for [ParameterSet.KeyGen].
+//
+// A background goroutine refills the pool up to its watermark as pairs are taken.
+// Each pair is handed out at most once. A KeyPool is safe for concurrent use.
+type KeyPool struct {
+	p         ParameterSet
+	keys      chan keyPair
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+
+	hits, misses atomic.Uint64
+}
+
+type keyPair struct {
+	ek EncapsulationKey
+	dk DecapsulationKey
+}
+
+// KeyPoolStats reports the usage of a [KeyPool].
+type KeyPoolStats struct {
+	Hits      uint64 // key pairs taken from the pool
+	Misses    uint64 // key pairs generated on demand because the pool was empty
+	Available int    // key pairs currently in the pool
+}
+
+// NewKeyPool returns a pool that keeps up to watermark key pairs ready.
+// It panics if watermark is not positive.
+// The pool must be closed with [KeyPool.Close] to stop the background goroutine.
+func (p ParameterSet) NewKeyPool(watermark int) *KeyPool {
+	if watermark <= 0 {
+		panic("mlkem: non-positive key pool watermark")
+	}
+	p.params() // panic early on invalid parameter set
+	kp := &KeyPool{
+		p:    p,
+		keys: make(chan keyPair, watermark),
+		done: make(chan struct{}),
+	}
+	kp.wg.Go(kp.refill)
+	return kp
+}
+
+func (kp *KeyPool) refill() {
+	for {
+		ek, dk := kp.p.KeyGen()
+		select {
+		case kp.keys <- keyPair{ek, dk}:
+		case <-kp.done:
+			clear(dk)
+			return
+		}
+	}
+}
+
+// ParameterSet returns the parameter set of the keys.
+func (kp *KeyPool) ParameterSet() ParameterSet {
+	return kp.p
+}
+
+// KeyGen returns a key pair from the pool,
+// or generates a new one with [ParameterSet.KeyGen] if the pool is empty or closed.
+func (kp *KeyPool) KeyGen() (EncapsulationKey, DecapsulationKey) {
+	select {
+	case k := <-kp.keys:
+		kp.hits.Add(1)
+		return k.ek, k.dk
+	default:
+		kp.misses.Add(1)
+		return kp.p.KeyGen()
+	}
+}
+
+// Stats returns the usage statistics of the pool.
+func (kp *KeyPool) Stats() KeyPoolStats {
+	return KeyPoolStats{
+		Hits:      kp.hits.Load(),
+		Misses:    kp.misses.Load(),
+		Available: len(kp.keys),
+	}
+}
+
+// Close stops refilling the pool and zeroizes the decapsulation keys that were not handed out.
+// Calling KeyGen after Close generates key pairs on demand.
+func (kp *KeyPool) Close() {
+	kp.closeOnce.Do(func() {
+		close(kp.done)
+		kp.wg.Wait()
+		for {
+			select {
+			case k := <-kp.keys:
+				k.dk.Zeroize()
+			default:
+				return
+			}
+		}
+	})
+}
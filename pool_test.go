@@ -0,0 +1,69 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestKeyPool(t *testing.T) {
+	const watermark = 8
+	p := mlkem.MLKEM_768
+	kp := p.NewKeyPool(watermark)
+	defer kp.Close()
+
+	for deadline := time.Now().Add(10 * time.Second); kp.Stats().Available < watermark; {
+		if time.Now().After(deadline) {
+			t.Fatalf("pool did not fill up: %+v", kp.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	const goroutines, perGoroutine = 4, 5
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range perGoroutine {
+				ek, dk := kp.KeyGen()
+				K1, c, err := p.Encaps(ek)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				K2, err := p.Decaps(dk, c)
+				if err != nil || !bytes.Equal(K1, K2) {
+					t.Errorf("key pair does not decapsulate: %v", err)
+				}
+				mu.Lock()
+				if seen[string(dk)] {
+					t.Error("key pair handed out twice")
+				}
+				seen[string(dk)] = true
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	s := kp.Stats()
+	if s.Hits+s.Misses != goroutines*perGoroutine {
+		t.Errorf("unexpected stats: %+v", s)
+	}
+	if s.Hits < watermark {
+		t.Errorf("expected at least %d hits: %+v", watermark, s)
+	}
+
+	kp.Close()
+	if s := kp.Stats(); s.Available != 0 {
+		t.Errorf("pool not drained on Close: %+v", s)
+	}
+	if ek, dk := kp.KeyGen(); ek == nil || dk == nil {
+		t.Error("KeyGen after Close")
+	}
+	kp.Close()
+}
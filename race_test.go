@@ -0,0 +1,7 @@
+//go:build race
+
+package mlkem_test
+
+// raceEnabled reports whether the race detector is enabled,
+// which adds allocations and makes AllocsPerRun checks meaningless.
+const raceEnabled = true
@@ -0,0 +1,98 @@
+package mlkem
+
+import (
+	"encoding/asn1"
+	"slices"
+)
+
+const (
+	// SeedSize is the size of the d‖z seed from which a key pair is derived.
+	SeedSize = 64
+	// SharedKeySize is the size of the shared key.
+	SharedKeySize = 32
+)
+
+// ParameterSets returns all parameter sets in the order of increasing security.
+func ParameterSets() []ParameterSet {
+	return []ParameterSet{MLKEM_512, MLKEM_768, MLKEM_1024}
+}
+
+// Valid reports whether p is one of the defined parameter sets.
+func (p ParameterSet) Valid() bool {
+	return p >= MLKEM_512 && p <= MLKEM_1024
+}
+
+// EncapsulationKeySize returns the size of the encapsulation key, 384k+32 bytes.
+func (p ParameterSet) EncapsulationKeySize() int {
+	return 384*p.k() + 32
+}
+
+// DecapsulationKeySize returns the size of the expanded decapsulation key, 768k+96 bytes.
+func (p ParameterSet) DecapsulationKeySize() int {
+	return 768*p.k() + 96
+}
+
+// CiphertextSize returns the size of the ciphertext, 32(dᵤk+dᵥ) bytes.
+func (p ParameterSet) CiphertextSize() int {
+	return 32 * (p.du()*p.k() + p.dv())
+}
+
+// SeedSize returns the size of the d‖z seed, see [SeedSize].
+func (p ParameterSet) SeedSize() int {
+	return SeedSize
+}
+
+// SharedKeySize returns the size of the shared key, see [SharedKeySize].
+func (p ParameterSet) SharedKeySize() int {
+	return SharedKeySize
+}
+
+// SecurityCategory returns the NIST security category claimed for the parameter set (FIPS 203 §8).
+func (p ParameterSet) SecurityCategory() int {
+	return p.params().category
+}
+
+// OID returns the object identifier of the parameter set assigned by NIST
+// in the Computer Security Objects Register, e.g. 2.16.840.1.101.3.4.4.2 for ML-KEM-768.
+func (p ParameterSet) OID() asn1.ObjectIdentifier {
+	return slices.Clone(p.params().oid)
+}
+
+// TLSGroup returns the TLS NamedGroup code point of the parameter set, e.g. 0x0201 for ML-KEM-768.
+func (p ParameterSet) TLSGroup() uint16 {
+	return p.params().tlsGroup
+}
+
+// HPKEKEM returns the HPKE KEM identifier of the parameter set, e.g. 0x0041 for ML-KEM-768.
+func (p ParameterSet) HPKEKEM() uint16 {
+	return p.params().hpkeKEM
+}
+
+// ParameterSetByName returns the parameter set with the given name, e.g. "ML-KEM-768".
+func ParameterSetByName(name string) (ParameterSet, bool) {
+	return lookup(func(p *parameters) bool { return p.name == name })
+}
+
+// ParameterSetByOID returns the parameter set with the given object identifier.
+func ParameterSetByOID(oid asn1.ObjectIdentifier) (ParameterSet, bool) {
+	return lookup(func(p *parameters) bool { return oid.Equal(p.oid) })
+}
+
+// ParameterSetByTLSGroup returns the parameter set with the given TLS NamedGroup code point.
+func ParameterSetByTLSGroup(id uint16) (ParameterSet, bool) {
+	return lookup(func(p *parameters) bool { return p.tlsGroup == id })
+}
+
+// ParameterSetByHPKEKEM returns the parameter set with the given HPKE KEM identifier.
+func ParameterSetByHPKEKEM(id uint16) (ParameterSet, bool) {
+	return lookup(func(p *parameters) bool { return p.hpkeKEM == id })
+}
+
+func lookup(match func(*parameters) bool) (ParameterSet, bool) {
+	for _, p := range ParameterSets() {
+		if match(p.params()) {
+			return p, true
+		}
+	}
+	return 0, false
+}
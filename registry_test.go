@@ -0,0 +1,118 @@
+package mlkem_test
+
+import (
+	"encoding/asn1"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestRegistry(t *testing.T) {
+	for _, tc := range []struct {
+		p                                mlkem.ParameterSet
+		name                             string
+		oid                              asn1.ObjectIdentifier
+		tlsGroup, hpkeKEM                uint16
+		ekSize, dkSize, ctSize, category int
+	}{
+		{mlkem.MLKEM_512, "ML-KEM-512", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1}, 0x0200, 0x0040, 800, 1632, 768, 1},
+		{mlkem.MLKEM_768, "ML-KEM-768", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}, 0x0201, 0x0041, 1184, 2400, 1088, 3},
+		{mlkem.MLKEM_1024, "ML-KEM-1024", asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}, 0x0202, 0x0042, 1568, 3168, 1568, 5},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.p
+			if p.String() != tc.name {
+				t.Errorf("name: %s", p)
+			}
+			if !p.OID().Equal(tc.oid) {
+				t.Errorf("OID: %v", p.OID())
+			}
+			if p.TLSGroup() != tc.tlsGroup {
+				t.Errorf("TLS group: %#04x", p.TLSGroup())
+			}
+			if p.HPKEKEM() != tc.hpkeKEM {
+				t.Errorf("HPKE KEM: %#04x", p.HPKEKEM())
+			}
+			if p.SecurityCategory() != tc.category {
+				t.Errorf("security category: %d", p.SecurityCategory())
+			}
+
+			ek, dk, seed := p.KeyGenWithSeed()
+			K, c, err := p.Encaps(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range []struct {
+				name           string
+				size, expected int
+				actual         int
+			}{
+				{"EncapsulationKeySize", p.EncapsulationKeySize(), tc.ekSize, len(ek)},
+				{"DecapsulationKeySize", p.DecapsulationKeySize(), tc.dkSize, len(dk)},
+				{"CiphertextSize", p.CiphertextSize(), tc.ctSize, len(c)},
+				{"SeedSize", p.SeedSize(), mlkem.SeedSize, len(seed)},
+				{"SharedKeySize", p.SharedKeySize(), mlkem.SharedKeySize, len(K)},
+			} {
+				if s.size != s.expected || s.size != s.actual {
+					t.Errorf("%s: %d, expected %d, actual %d", s.name, s.size, s.expected, s.actual)
+				}
+			}
+
+			lookups := []struct {
+				name string
+				find func() (mlkem.ParameterSet, bool)
+			}{
+				{"name", func() (mlkem.ParameterSet, bool) { return mlkem.ParameterSetByName(tc.name) }},
+				{"OID", func() (mlkem.ParameterSet, bool) { return mlkem.ParameterSetByOID(tc.oid) }},
+				{"TLS group", func() (mlkem.ParameterSet, bool) { return mlkem.ParameterSetByTLSGroup(tc.tlsGroup) }},
+				{"HPKE KEM", func() (mlkem.ParameterSet, bool) { return mlkem.ParameterSetByHPKEKEM(tc.hpkeKEM) }},
+			}
+			for _, l := range lookups {
+				if found, ok := l.find(); !ok || found != p {
+					t.Errorf("lookup by %s: %v, %v", l.name, found, ok)
+				}
+			}
+
+			oid := p.OID()
+			oid[0] = 0
+			if !p.OID().Equal(tc.oid) {
+				t.Error("OID was modified")
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, ok := mlkem.ParameterSetByName("ML-KEM-2048"); ok {
+			t.Error("unexpected name match")
+		}
+		if _, ok := mlkem.ParameterSetByOID(asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 4}); ok {
+			t.Error("unexpected OID match")
+		}
+		if _, ok := mlkem.ParameterSetByTLSGroup(0x11ec); ok {
+			t.Error("unexpected TLS group match")
+		}
+		if _, ok := mlkem.ParameterSetByHPKEKEM(0x0020); ok {
+			t.Error("unexpected HPKE KEM match")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var p mlkem.ParameterSet
+		if p.Valid() {
+			t.Error("zero value is valid")
+		}
+		if p.String() != "ParameterSet(0)" {
+			t.Errorf("unexpected name: %s", p)
+		}
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		p.KeyGen()
+	})
+
+	if len(mlkem.ParameterSets()) != 3 {
+		t.Errorf("unexpected parameter sets: %v", mlkem.ParameterSets())
+	}
+}
@@ -0,0 +1,235 @@
+package mlkem
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// Secret key material can be wiped with Zeroize, for byte values, or Destroy, for parsed keys.
+// Go may have copied the values elsewhere in memory, e.g. when growing the stack,
+// so wiping is best-effort. The same applies to the temporaries d, z, σ, ŝ, m, r and K̄
+// which are zeroed after every operation.
+//
+// The secret types implement [fmt.Formatter], [fmt.Stringer], [fmt.GoStringer] and,
+// where the value itself would be serialized, [encoding/json.Marshaler]
+// so that they are not leaked by logging or encoding the containing value.
+// Use Bytes or a conversion to []byte to access the value.
+
+const redacted = "REDACTED"
+
+// Zeroize overwrites the decapsulation key with zeros.
+func (dk DecapsulationKey) Zeroize() {
+	clear(dk)
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (dk DecapsulationKey) Format(f fmt.State, verb rune) {
+	io.WriteString(f, dk.String())
+}
+
+// String returns a placeholder instead of the key.
+func (dk DecapsulationKey) String() string {
+	return "mlkem.DecapsulationKey(" + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (dk DecapsulationKey) GoString() string {
+	return dk.String()
+}
+
+// MarshalJSON implements [encoding/json.Marshaler] and encodes a placeholder instead of the key.
+func (dk DecapsulationKey) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// Zeroize overwrites the shared key with zeros.
+func (K SharedKey) Zeroize() {
+	clear(K)
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (K SharedKey) Format(f fmt.State, verb rune) {
+	io.WriteString(f, K.String())
+}
+
+// String returns a placeholder instead of the key.
+func (K SharedKey) String() string {
+	return "mlkem.SharedKey(" + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (K SharedKey) GoString() string {
+	return K.String()
+}
+
+// MarshalJSON implements [encoding/json.Marshaler] and encodes a placeholder instead of the key.
+func (K SharedKey) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// Destroy zeroes the secret vector ŝ, the implicit rejection value z and the seed.
+// Afterwards [Decapsulator.Decapsulate] returns [ErrKeyDestroyed],
+// [Decapsulator.Bytes] returns nil and [Decapsulator.Seed] returns false.
+// Destroy must not be called concurrently with other methods.
+func (d *Decapsulator) Destroy() {
+	d.destroyed = true
+	if d.dk != nil {
+		d.dk.Destroy()
+	}
+	clear(d.seed)
+	runtime.KeepAlive(d)
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (d *Decapsulator) Format(f fmt.State, verb rune) {
+	io.WriteString(f, d.String())
+}
+
+// String returns the parameter set and a placeholder instead of the key.
+func (d *Decapsulator) String() string {
+	return "mlkem.Decapsulator(" + d.p.String() + ", " + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (d *Decapsulator) GoString() string {
+	return d.String()
+}
+
+// Destroy zeroes the seed and destroys the expanded key, see [Decapsulator.Destroy].
+// Afterwards [SeedDecapsulator.Seed] and [SeedDecapsulator.Bytes] return nil.
+// A key that was not expanded yet is not expanded anymore,
+// so its [SeedDecapsulator.EncapsulationKey] returns nil.
+func (s *SeedDecapsulator) Destroy() {
+	s.destroyed = true
+	clear(s.seed)
+	s.Decapsulator().Destroy()
+	runtime.KeepAlive(s)
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (s *SeedDecapsulator) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
+
+// String returns the parameter set and a placeholder instead of the key.
+func (s *SeedDecapsulator) String() string {
+	return "mlkem.SeedDecapsulator(" + s.p.String() + ", " + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (s *SeedDecapsulator) GoString() string {
+	return s.String()
+}
+
+// Destroy destroys the key, see [Decapsulator.Destroy].
+func (dk *DecapsulationKey512) Destroy() { dk.d.Destroy() }
+
+// Destroy destroys the key, see [Decapsulator.Destroy].
+func (dk *DecapsulationKey768) Destroy() { dk.d.Destroy() }
+
+// Destroy destroys the key, see [Decapsulator.Destroy].
+func (dk *DecapsulationKey1024) Destroy() { dk.d.Destroy() }
+
+// Destroy overwrites the key with zeros.
+// Afterwards [FixedDecapsulationKey512.Decaps] returns [ErrDecapsulationKeyHash].
+func (dk *FixedDecapsulationKey512) Destroy() {
+	clear(dk[:])
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (dk FixedDecapsulationKey512) Format(f fmt.State, verb rune) {
+	io.WriteString(f, dk.String())
+}
+
+// String returns a placeholder instead of the key.
+func (dk FixedDecapsulationKey512) String() string {
+	return "mlkem.FixedDecapsulationKey512(" + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (dk FixedDecapsulationKey512) GoString() string {
+	return dk.String()
+}
+
+// MarshalJSON implements [encoding/json.Marshaler] and encodes a placeholder instead of the key.
+func (dk FixedDecapsulationKey512) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// Destroy overwrites the key with zeros.
+// Afterwards [FixedDecapsulationKey768.Decaps] returns [ErrDecapsulationKeyHash].
+func (dk *FixedDecapsulationKey768) Destroy() {
+	clear(dk[:])
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (dk FixedDecapsulationKey768) Format(f fmt.State, verb rune) {
+	io.WriteString(f, dk.String())
+}
+
+// String returns a placeholder instead of the key.
+func (dk FixedDecapsulationKey768) String() string {
+	return "mlkem.FixedDecapsulationKey768(" + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (dk FixedDecapsulationKey768) GoString() string {
+	return dk.String()
+}
+
+// MarshalJSON implements [encoding/json.Marshaler] and encodes a placeholder instead of the key.
+func (dk FixedDecapsulationKey768) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// Destroy overwrites the key with zeros.
+// Afterwards [FixedDecapsulationKey1024.Decaps] returns [ErrDecapsulationKeyHash].
+func (dk *FixedDecapsulationKey1024) Destroy() {
+	clear(dk[:])
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (dk FixedDecapsulationKey1024) Format(f fmt.State, verb rune) {
+	io.WriteString(f, dk.String())
+}
+
+// String returns a placeholder instead of the key.
+func (dk FixedDecapsulationKey1024) String() string {
+	return "mlkem.FixedDecapsulationKey1024(" + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (dk FixedDecapsulationKey1024) GoString() string {
+	return dk.String()
+}
+
+// MarshalJSON implements [encoding/json.Marshaler] and encodes a placeholder instead of the key.
+func (dk FixedDecapsulationKey1024) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// Zeroize overwrites the shared key with zeros.
+func (K *FixedSharedKey) Zeroize() {
+	clear(K[:])
+}
+
+// Format implements [fmt.Formatter] and prints the String placeholder for every verb.
+func (K FixedSharedKey) Format(f fmt.State, verb rune) {
+	io.WriteString(f, K.String())
+}
+
+// String returns a placeholder instead of the key.
+func (K FixedSharedKey) String() string {
+	return "mlkem.FixedSharedKey(" + redacted + ")"
+}
+
+// GoString returns a placeholder instead of the key.
+func (K FixedSharedKey) GoString() string {
+	return K.String()
+}
+
+// MarshalJSON implements [encoding/json.Marshaler] and encodes a placeholder instead of the key.
+func (K FixedSharedKey) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
@@ -0,0 +1,212 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestRedacted(t *testing.T) {
+	p := mlkem.MLKEM_768
+	ek, dk := p.KeyGen()
+	K, _, err := p.Encaps(ek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := p.NewDecapsulatorFromSeed(bytes.Repeat([]byte{0xa5}, mlkem.SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.NewSeedDecapsulator(bytes.Repeat([]byte{0x5a}, mlkem.SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fdk := mlkem.GenerateFixed768()
+	fek := fdk.EncapsulationKey()
+	fK, _, err := fek.Encaps()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Prefixes of the secret values in hexadecimal and decimal.
+	secrets := []string{
+		hex.EncodeToString(dk[:8]), fmt.Sprint([]byte(dk[:8])),
+		hex.EncodeToString(K[:8]), fmt.Sprint([]byte(K[:8])),
+		"a5a5a5a5", "165 165 165 165",
+		"5a5a5a5a", "90 90 90 90",
+		hex.EncodeToString(fK[:8]), fmt.Sprint(fK[:8]),
+	}
+	dkb := fdk.Bytes()
+	secrets = append(secrets, hex.EncodeToString(dkb[:8]), fmt.Sprint([]byte(dkb[:8])))
+
+	for _, v := range []any{
+		dk, K, d, s, fdk, &fdk, fK, &fK,
+		struct {
+			DK mlkem.DecapsulationKey
+			K  mlkem.SharedKey
+			D  *mlkem.Decapsulator
+			S  *mlkem.SeedDecapsulator
+			F  mlkem.FixedDecapsulationKey768
+			FK mlkem.FixedSharedKey
+		}{dk, K, d, s, fdk, fK},
+	} {
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%X", "%d", "%T %[1]v"} {
+			out := fmt.Sprintf(verb, v)
+			if !strings.Contains(out, "REDACTED") {
+				t.Errorf("%s of %T is not redacted: %s", verb, v, out)
+			}
+			for _, secret := range secrets {
+				if strings.Contains(strings.ToLower(out), secret) {
+					t.Errorf("%s of %T leaks %s: %s", verb, v, secret, out)
+				}
+			}
+		}
+		if _, ok := v.(*mlkem.Decapsulator); ok {
+			continue // not serialized, it has no exported fields
+		}
+		if _, ok := v.(*mlkem.SeedDecapsulator); ok {
+			continue
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(out, []byte("REDACTED")) {
+			t.Errorf("JSON of %T is not redacted: %s", v, out)
+		}
+	}
+
+	if got, want := d.String(), "mlkem.Decapsulator(ML-KEM-768, REDACTED)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%#v", fdk), "mlkem.FixedDecapsulationKey768(REDACTED)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDestroy(t *testing.T) {
+	p := mlkem.MLKEM_768
+	isZero := func(b []byte) bool { return bytes.Count(b, []byte{0}) == len(b) }
+
+	t.Run("Zeroize", func(t *testing.T) {
+		ek, dk := p.KeyGen()
+		K, _, err := p.Encaps(ek)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dk.Zeroize()
+		K.Zeroize()
+		if !isZero(dk) || !isZero(K) {
+			t.Error("not zeroized")
+		}
+
+		_, fdk := mlkem.GenerateFixed768()
+		fek := fdk.EncapsulationKey()
+		fK, c, err := fek.Encaps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fK.Zeroize()
+		if fK != (mlkem.FixedSharedKey{}) {
+			t.Error("not zeroized")
+		}
+		fdk.Destroy()
+		if _, err := fdk.Decaps(&c); !errors.Is(err, mlkem.ErrDecapsulationKeyHash) {
+			t.Errorf("expected hash error, got: %v", err)
+		}
+	})
+
+	t.Run("Decapsulator", func(t *testing.T) {
+		d1, err := p.GenerateDecapsulator()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d2, err := p.NewDecapsulator(d1.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := d1.EncapsulationKey()
+		_, c := e.Encapsulate()
+		for _, d := range []*mlkem.Decapsulator{d1, d2} {
+			d.Destroy()
+			if _, err := d.Decapsulate(c); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+				t.Errorf("expected destroyed error, got: %v", err)
+			}
+			if _, err := d.DecapsulateTo(nil, c); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+				t.Errorf("expected destroyed error, got: %v", err)
+			}
+			if seed, ok := d.Seed(); seed != nil || ok {
+				t.Errorf("Seed() = %x, %v after Destroy", seed, ok)
+			}
+			if dk := d.Bytes(); dk != nil {
+				t.Error("Bytes() is not nil after Destroy")
+			}
+		}
+		// The encapsulation key is public and stays usable.
+		if !bytes.Equal(d1.EncapsulationKey().Bytes(), e.Bytes()) {
+			t.Error("encapsulation key changed")
+		}
+	})
+
+	t.Run("SeedDecapsulator", func(t *testing.T) {
+		s, err := p.GenerateSeedDecapsulator()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, c := s.EncapsulationKey().Encapsulate()
+		s.Destroy()
+		if _, err := s.Decapsulate(c); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+			t.Errorf("expected destroyed error, got: %v", err)
+		}
+		if s.Seed() != nil || s.Bytes() != nil {
+			t.Error("Seed() or Bytes() is not nil after Destroy")
+		}
+		if _, ok := s.Decapsulator().Seed(); ok {
+			t.Error("expanded key has a seed after Destroy")
+		}
+
+		// Destroy before the key is expanded.
+		s, err = p.GenerateSeedDecapsulator()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Destroy()
+		if _, err := s.Decapsulate(c); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+			t.Errorf("expected destroyed error, got: %v", err)
+		}
+		if s.Seed() != nil || s.Bytes() != nil || s.EncapsulationKey() != nil {
+			t.Error("Seed(), Bytes() or EncapsulationKey() is not nil after Destroy")
+		}
+	})
+
+	t.Run("Std", func(t *testing.T) {
+		dk512, err := mlkem.GenerateKey512()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dk768, err := mlkem.GenerateKey768()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dk1024, err := mlkem.GenerateKey1024()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, c := dk768.EncapsulationKey().Encapsulate()
+		dk512.Destroy()
+		dk768.Destroy()
+		dk1024.Destroy()
+		if _, err := dk768.Decapsulate(c); !errors.Is(err, mlkem.ErrKeyDestroyed) {
+			t.Errorf("expected destroyed error, got: %v", err)
+		}
+		if dk512.Bytes() != nil || dk768.Bytes() != nil || dk1024.Bytes() != nil {
+			t.Error("Bytes() is not nil after Destroy")
+		}
+	})
+}
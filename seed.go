@@ -0,0 +1,138 @@
+package mlkem
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// KeyGenWithSeed is like [ParameterSet.KeyGen] but also returns the 64-byte d‖z seed
+// from which the keys were derived.
+// Storing the seed instead of the decapsulation key is recommended by FIPS 203 §3.3.
+func (p ParameterSet) KeyGenWithSeed() (EncapsulationKey, DecapsulationKey, []byte) {
+	ek, dk, seed, _ := p.KeyGenWithSeedRand(rand.Reader) // crypto/rand.Reader never fails
+	return ek, dk, seed
+}
+
+// KeyGenWithSeedRand is like [ParameterSet.KeyGenWithSeed] but reads randomness from rand.
+// It returns an error if rand fails or does not provide enough bytes.
+func (p ParameterSet) KeyGenWithSeedRand(rand io.Reader) (EncapsulationKey, DecapsulationKey, []byte, error) {
+	if err := p.checkValid("KeyGenWithSeedRand"); err != nil {
+		return nil, nil, nil, err
+	}
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, nil, nil, p.entropyError("KeyGenWithSeedRand", err)
+	}
+	ek, dk := p.keyGen(nil, nil, seed[:32], seed[32:])
+	return ek, dk, seed, nil
+}
+
+// ExpandSeed converts the 64-byte d‖z seed into the expanded decapsulation key.
+// The reverse conversion is not possible as the expanded form does not contain d.
+func (p ParameterSet) ExpandSeed(seed []byte) (DecapsulationKey, error) {
+	if err := p.checkValid("ExpandSeed"); err != nil {
+		return nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, p.newError("ExpandSeed", "seed", ErrInvalidLength)
+	}
+	_, dk := p.keyGen(nil, nil, seed[:32], seed[32:])
+	return dk, nil
+}
+
+// SeedDecapsulator is a decapsulation key stored in the 64-byte d‖z seed form.
+// The key is expanded on first use and the expanded form is cached.
+// It is safe for concurrent use.
+type SeedDecapsulator struct {
+	p    ParameterSet
+	seed []byte
+	once sync.Once
+	d    *Decapsulator
+
+	mem       *lockedMemory // non-nil if seed and the expanded key are in locked memory
+	destroyed bool
+}
+
+// GenerateSeedDecapsulator generates a new seed-backed decapsulation key.
+func (p ParameterSet) GenerateSeedDecapsulator() (*SeedDecapsulator, error) {
+	return p.GenerateSeedDecapsulatorRand(rand.Reader)
+}
+
+// GenerateSeedDecapsulatorRand is like [ParameterSet.GenerateSeedDecapsulator] but reads randomness from rand.
+// It returns an error if rand fails or does not provide enough bytes.
+func (p ParameterSet) GenerateSeedDecapsulatorRand(rand io.Reader) (*SeedDecapsulator, error) {
+	if err := p.checkValid("GenerateSeedDecapsulatorRand"); err != nil {
+		return nil, err
+	}
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, p.entropyError("GenerateSeedDecapsulatorRand", err)
+	}
+	return &SeedDecapsulator{p: p, seed: seed}, nil
+}
+
+// NewSeedDecapsulator creates a decapsulation key from the 64-byte d‖z seed without expanding it.
+func (p ParameterSet) NewSeedDecapsulator(seed []byte) (*SeedDecapsulator, error) {
+	if err := p.checkValid("NewSeedDecapsulator"); err != nil {
+		return nil, err
+	}
+	if len(seed) != SeedSize {
+		return nil, p.newError("NewSeedDecapsulator", "seed", ErrInvalidLength)
+	}
+	return &SeedDecapsulator{p: p, seed: bytes.Clone(seed)}, nil
+}
+
+// ParameterSet returns the parameter set of the key.
+func (s *SeedDecapsulator) ParameterSet() ParameterSet {
+	return s.p
+}
+
+// Seed returns the 64-byte d‖z seed of the key.
+// It returns nil after [SeedDecapsulator.Destroy].
+func (s *SeedDecapsulator) Seed() []byte {
+	if s.destroyed {
+		return nil
+	}
+	seed := bytes.Clone(s.seed)
+	runtime.KeepAlive(s)
+	return seed
+}
+
+// Decapsulator returns the expanded key, expanding it on first use.
+func (s *SeedDecapsulator) Decapsulator() *Decapsulator {
+	s.once.Do(func() {
+		switch {
+		case s.destroyed:
+			s.d = &Decapsulator{p: s.p, destroyed: true}
+		case s.mem != nil:
+			s.d = s.p.expandLocked(s.mem)
+		default:
+			s.d, _ = s.p.NewDecapsulatorFromSeed(s.seed) // seed length is checked by the constructors
+		}
+	})
+	return s.d
+}
+
+// Bytes returns the decapsulation key in its expanded form.
+func (s *SeedDecapsulator) Bytes() DecapsulationKey {
+	return s.Decapsulator().Bytes()
+}
+
+// EncapsulationKey returns the public encapsulation key corresponding to the decapsulation key.
+func (s *SeedDecapsulator) EncapsulationKey() *Encapsulator {
+	return s.Decapsulator().EncapsulationKey()
+}
+
+// Decapsulate accepts a ciphertext and outputs a shared key.
+func (s *SeedDecapsulator) Decapsulate(c Ciphertext) (SharedKey, error) {
+	return s.Decapsulator().Decapsulate(c)
+}
+
+// DecapsulateTo is like [SeedDecapsulator.Decapsulate] but appends the shared key to dst
+// and returns the extended buffer.
+func (s *SeedDecapsulator) DecapsulateTo(dst []byte, c Ciphertext) (SharedKey, error) {
+	return s.Decapsulator().DecapsulateTo(dst, c)
+}
@@ -0,0 +1,123 @@
+package mlkem_test
+
+import (
+	"bytes"
+	stdmlkem "crypto/mlkem"
+	"sync"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+func TestSeed(t *testing.T) {
+	for _, p := range []mlkem.ParameterSet{
+		mlkem.MLKEM_512, mlkem.MLKEM_768, mlkem.MLKEM_1024,
+	} {
+		t.Run(p.String(), func(t *testing.T) {
+			ek, dk, seed := p.KeyGenWithSeed()
+			if len(seed) != mlkem.SeedSize {
+				t.Fatalf("invalid seed length: %d", len(seed))
+			}
+
+			dk2, err := p.ExpandSeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(dk, dk2) {
+				t.Error("ExpandSeed does not match KeyGenWithSeed")
+			}
+
+			d, err := p.NewDecapsulatorFromSeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s, ok := d.Seed(); !ok || !bytes.Equal(seed, s) {
+				t.Error("Decapsulator.Seed does not round-trip")
+			}
+
+			d2, err := p.NewDecapsulator(dk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := d2.Seed(); ok {
+				t.Error("expected no seed for the expanded form")
+			}
+
+			s, err := p.NewSeedDecapsulator(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(seed, s.Seed()) {
+				t.Error("SeedDecapsulator.Seed does not round-trip")
+			}
+			if !bytes.Equal(ek, s.EncapsulationKey().Bytes()) {
+				t.Error("ek mismatch")
+			}
+			if !bytes.Equal(dk, s.Bytes()) {
+				t.Error("dk mismatch")
+			}
+
+			K1, c, err := p.Encaps(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+			K2, err := s.Decapsulate(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(K1, K2) {
+				t.Errorf("%x != %x", K1, K2)
+			}
+
+			if _, err := p.ExpandSeed(seed[1:]); err == nil {
+				t.Error("ExpandSeed: expected error for short seed")
+			}
+			if _, err := p.NewSeedDecapsulator(seed[1:]); err == nil {
+				t.Error("NewSeedDecapsulator: expected error for short seed")
+			}
+		})
+	}
+}
+
+func TestSeedDecapsulatorConcurrent(t *testing.T) {
+	s, err := mlkem.MLKEM_768.GenerateSeedDecapsulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	ds := make([]*mlkem.Decapsulator, 8)
+	for i := range ds {
+		wg.Go(func() {
+			ds[i] = s.Decapsulator()
+		})
+	}
+	wg.Wait()
+
+	for _, d := range ds {
+		if d != ds[0] {
+			t.Fatal("key expanded more than once")
+		}
+	}
+}
+
+func TestSeedCompatibility(t *testing.T) {
+	dk, err := stdmlkem.GenerateKey768()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := mlkem.MLKEM_768.NewSeedDecapsulator(dk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	K1, c := dk.EncapsulationKey().Encapsulate()
+	K2, err := s.Decapsulate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(K1, K2) {
+		t.Error("K1 != K2")
+	}
+}
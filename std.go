@@ -0,0 +1,330 @@
+package mlkem
+
+import (
+	stdmlkem "crypto/mlkem"
+)
+
+// The types in this file wrap the keys of this package behind the method set of
+// the [crypto/mlkem] types, so that the two implementations can be used interchangeably.
+// Unlike the standard library, ML-KEM-512 is supported as well.
+//
+// As in the standard library, decapsulation keys are represented by their 64-byte d‖z seed.
+
+// DecapsulationKey512 is an ML-KEM-512 decapsulation key with the method set of crypto/mlkem.DecapsulationKey768.
+type DecapsulationKey512 struct {
+	d *Decapsulator
+}
+
+// EncapsulationKey512 is an ML-KEM-512 encapsulation key with the method set of crypto/mlkem.EncapsulationKey768.
+type EncapsulationKey512 struct {
+	e *Encapsulator
+}
+
+// GenerateKey512 generates a new ML-KEM-512 decapsulation key.
+func GenerateKey512() (*DecapsulationKey512, error) {
+	d, err := MLKEM_512.GenerateDecapsulator()
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey512{d: d}, nil
+}
+
+// NewDecapsulationKey512 parses an ML-KEM-512 decapsulation key from its 64-byte d‖z seed.
+func NewDecapsulationKey512(seed []byte) (*DecapsulationKey512, error) {
+	d, err := MLKEM_512.NewDecapsulatorFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey512{d: d}, nil
+}
+
+// NewDecapsulationKey512FromDecapsulator wraps d which must be an ML-KEM-512 key created from a seed.
+func NewDecapsulationKey512FromDecapsulator(d *Decapsulator) (*DecapsulationKey512, error) {
+	if d.p != MLKEM_512 {
+		return nil, MLKEM_512.newError("NewDecapsulationKey512FromDecapsulator", "d", ErrParameterSetMismatch)
+	}
+	if d.seed == nil {
+		return nil, MLKEM_512.newError("NewDecapsulationKey512FromDecapsulator", "d", ErrNoSeed)
+	}
+	return &DecapsulationKey512{d: d}, nil
+}
+
+// Bytes returns the decapsulation key as a 64-byte d‖z seed.
+// It returns nil after Destroy.
+func (dk *DecapsulationKey512) Bytes() []byte {
+	seed, _ := dk.d.Seed()
+	return seed
+}
+
+// EncapsulationKey returns the public encapsulation key corresponding to the decapsulation key.
+func (dk *DecapsulationKey512) EncapsulationKey() *EncapsulationKey512 {
+	return &EncapsulationKey512{e: dk.d.EncapsulationKey()}
+}
+
+// Decapsulate generates a shared key from a ciphertext and the decapsulation key.
+func (dk *DecapsulationKey512) Decapsulate(ciphertext []byte) (sharedKey []byte, err error) {
+	return dk.d.Decapsulate(ciphertext)
+}
+
+// Decapsulator returns the underlying decapsulation key.
+func (dk *DecapsulationKey512) Decapsulator() *Decapsulator {
+	return dk.d
+}
+
+// NewEncapsulationKey512 parses an ML-KEM-512 encapsulation key.
+func NewEncapsulationKey512(encapsulationKey []byte) (*EncapsulationKey512, error) {
+	e, err := MLKEM_512.NewEncapsulator(encapsulationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncapsulationKey512{e: e}, nil
+}
+
+// NewEncapsulationKey512FromEncapsulator wraps e which must be an ML-KEM-512 key.
+func NewEncapsulationKey512FromEncapsulator(e *Encapsulator) (*EncapsulationKey512, error) {
+	if e.p != MLKEM_512 {
+		return nil, MLKEM_512.newError("NewEncapsulationKey512FromEncapsulator", "e", ErrParameterSetMismatch)
+	}
+	return &EncapsulationKey512{e: e}, nil
+}
+
+// Bytes returns the encapsulation key in its encoded form.
+func (ek *EncapsulationKey512) Bytes() []byte {
+	return ek.e.Bytes()
+}
+
+// Encapsulate generates a shared key and an associated ciphertext from the encapsulation key.
+func (ek *EncapsulationKey512) Encapsulate() (sharedKey, ciphertext []byte) {
+	return ek.e.Encapsulate()
+}
+
+// Encapsulator returns the underlying encapsulation key.
+func (ek *EncapsulationKey512) Encapsulator() *Encapsulator {
+	return ek.e
+}
+
+// DecapsulationKey768 is an ML-KEM-768 decapsulation key with the method set of [crypto/mlkem.DecapsulationKey768].
+type DecapsulationKey768 struct {
+	d *Decapsulator
+}
+
+// EncapsulationKey768 is an ML-KEM-768 encapsulation key with the method set of [crypto/mlkem.EncapsulationKey768].
+type EncapsulationKey768 struct {
+	e *Encapsulator
+}
+
+// GenerateKey768 generates a new ML-KEM-768 decapsulation key.
+func GenerateKey768() (*DecapsulationKey768, error) {
+	d, err := MLKEM_768.GenerateDecapsulator()
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey768{d: d}, nil
+}
+
+// NewDecapsulationKey768 parses an ML-KEM-768 decapsulation key from its 64-byte d‖z seed.
+func NewDecapsulationKey768(seed []byte) (*DecapsulationKey768, error) {
+	d, err := MLKEM_768.NewDecapsulatorFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey768{d: d}, nil
+}
+
+// NewDecapsulationKey768FromDecapsulator wraps d which must be an ML-KEM-768 key created from a seed.
+func NewDecapsulationKey768FromDecapsulator(d *Decapsulator) (*DecapsulationKey768, error) {
+	if d.p != MLKEM_768 {
+		return nil, MLKEM_768.newError("NewDecapsulationKey768FromDecapsulator", "d", ErrParameterSetMismatch)
+	}
+	if d.seed == nil {
+		return nil, MLKEM_768.newError("NewDecapsulationKey768FromDecapsulator", "d", ErrNoSeed)
+	}
+	return &DecapsulationKey768{d: d}, nil
+}
+
+// Bytes returns the decapsulation key as a 64-byte d‖z seed.
+// It returns nil after Destroy.
+func (dk *DecapsulationKey768) Bytes() []byte {
+	seed, _ := dk.d.Seed()
+	return seed
+}
+
+// EncapsulationKey returns the public encapsulation key corresponding to the decapsulation key.
+func (dk *DecapsulationKey768) EncapsulationKey() *EncapsulationKey768 {
+	return &EncapsulationKey768{e: dk.d.EncapsulationKey()}
+}
+
+// Decapsulate generates a shared key from a ciphertext and the decapsulation key.
+func (dk *DecapsulationKey768) Decapsulate(ciphertext []byte) (sharedKey []byte, err error) {
+	return dk.d.Decapsulate(ciphertext)
+}
+
+// Decapsulator returns the underlying decapsulation key.
+func (dk *DecapsulationKey768) Decapsulator() *Decapsulator {
+	return dk.d
+}
+
+// NewEncapsulationKey768 parses an ML-KEM-768 encapsulation key.
+func NewEncapsulationKey768(encapsulationKey []byte) (*EncapsulationKey768, error) {
+	e, err := MLKEM_768.NewEncapsulator(encapsulationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncapsulationKey768{e: e}, nil
+}
+
+// NewEncapsulationKey768FromEncapsulator wraps e which must be an ML-KEM-768 key.
+func NewEncapsulationKey768FromEncapsulator(e *Encapsulator) (*EncapsulationKey768, error) {
+	if e.p != MLKEM_768 {
+		return nil, MLKEM_768.newError("NewEncapsulationKey768FromEncapsulator", "e", ErrParameterSetMismatch)
+	}
+	return &EncapsulationKey768{e: e}, nil
+}
+
+// Bytes returns the encapsulation key in its encoded form.
+func (ek *EncapsulationKey768) Bytes() []byte {
+	return ek.e.Bytes()
+}
+
+// Encapsulate generates a shared key and an associated ciphertext from the encapsulation key.
+func (ek *EncapsulationKey768) Encapsulate() (sharedKey, ciphertext []byte) {
+	return ek.e.Encapsulate()
+}
+
+// Encapsulator returns the underlying encapsulation key.
+func (ek *EncapsulationKey768) Encapsulator() *Encapsulator {
+	return ek.e
+}
+
+// NewDecapsulationKey768FromStd converts a [crypto/mlkem.DecapsulationKey768].
+func NewDecapsulationKey768FromStd(dk *stdmlkem.DecapsulationKey768) (*DecapsulationKey768, error) {
+	return NewDecapsulationKey768(dk.Bytes())
+}
+
+// Std converts the key to a [crypto/mlkem.DecapsulationKey768].
+func (dk *DecapsulationKey768) Std() (*stdmlkem.DecapsulationKey768, error) {
+	return stdmlkem.NewDecapsulationKey768(dk.Bytes())
+}
+
+// NewEncapsulationKey768FromStd converts a [crypto/mlkem.EncapsulationKey768].
+func NewEncapsulationKey768FromStd(ek *stdmlkem.EncapsulationKey768) (*EncapsulationKey768, error) {
+	return NewEncapsulationKey768(ek.Bytes())
+}
+
+// Std converts the key to a [crypto/mlkem.EncapsulationKey768].
+func (ek *EncapsulationKey768) Std() (*stdmlkem.EncapsulationKey768, error) {
+	return stdmlkem.NewEncapsulationKey768(ek.Bytes())
+}
+
+// DecapsulationKey1024 is an ML-KEM-1024 decapsulation key with the method set of [crypto/mlkem.DecapsulationKey1024].
+type DecapsulationKey1024 struct {
+	d *Decapsulator
+}
+
+// EncapsulationKey1024 is an ML-KEM-1024 encapsulation key with the method set of [crypto/mlkem.EncapsulationKey1024].
+type EncapsulationKey1024 struct {
+	e *Encapsulator
+}
+
+// GenerateKey1024 generates a new ML-KEM-1024 decapsulation key.
+func GenerateKey1024() (*DecapsulationKey1024, error) {
+	d, err := MLKEM_1024.GenerateDecapsulator()
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey1024{d: d}, nil
+}
+
+// NewDecapsulationKey1024 parses an ML-KEM-1024 decapsulation key from its 64-byte d‖z seed.
+func NewDecapsulationKey1024(seed []byte) (*DecapsulationKey1024, error) {
+	d, err := MLKEM_1024.NewDecapsulatorFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey1024{d: d}, nil
+}
+
+// NewDecapsulationKey1024FromDecapsulator wraps d which must be an ML-KEM-1024 key created from a seed.
+func NewDecapsulationKey1024FromDecapsulator(d *Decapsulator) (*DecapsulationKey1024, error) {
+	if d.p != MLKEM_1024 {
+		return nil, MLKEM_1024.newError("NewDecapsulationKey1024FromDecapsulator", "d", ErrParameterSetMismatch)
+	}
+	if d.seed == nil {
+		return nil, MLKEM_1024.newError("NewDecapsulationKey1024FromDecapsulator", "d", ErrNoSeed)
+	}
+	return &DecapsulationKey1024{d: d}, nil
+}
+
+// Bytes returns the decapsulation key as a 64-byte d‖z seed.
+// It returns nil after Destroy.
+func (dk *DecapsulationKey1024) Bytes() []byte {
+	seed, _ := dk.d.Seed()
+	return seed
+}
+
+// EncapsulationKey returns the public encapsulation key corresponding to the decapsulation key.
+func (dk *DecapsulationKey1024) EncapsulationKey() *EncapsulationKey1024 {
+	return &EncapsulationKey1024{e: dk.d.EncapsulationKey()}
+}
+
+// Decapsulate generates a shared key from a ciphertext and the decapsulation key.
+func (dk *DecapsulationKey1024) Decapsulate(ciphertext []byte) (sharedKey []byte, err error) {
+	return dk.d.Decapsulate(ciphertext)
+}
+
+// Decapsulator returns the underlying decapsulation key.
+func (dk *DecapsulationKey1024) Decapsulator() *Decapsulator {
+	return dk.d
+}
+
+// NewEncapsulationKey1024 parses an ML-KEM-1024 encapsulation key.
+func NewEncapsulationKey1024(encapsulationKey []byte) (*EncapsulationKey1024, error) {
+	e, err := MLKEM_1024.NewEncapsulator(encapsulationKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncapsulationKey1024{e: e}, nil
+}
+
+// NewEncapsulationKey1024FromEncapsulator wraps e which must be an ML-KEM-1024 key.
+func NewEncapsulationKey1024FromEncapsulator(e *Encapsulator) (*EncapsulationKey1024, error) {
+	if e.p != MLKEM_1024 {
+		return nil, MLKEM_1024.newError("NewEncapsulationKey1024FromEncapsulator", "e", ErrParameterSetMismatch)
+	}
+	return &EncapsulationKey1024{e: e}, nil
+}
+
+// Bytes returns the encapsulation key in its encoded form.
+func (ek *EncapsulationKey1024) Bytes() []byte {
+	return ek.e.Bytes()
+}
+
+// Encapsulate generates a shared key and an associated ciphertext from the encapsulation key.
+func (ek *EncapsulationKey1024) Encapsulate() (sharedKey, ciphertext []byte) {
+	return ek.e.Encapsulate()
+}
+
+// Encapsulator returns the underlying encapsulation key.
+func (ek *EncapsulationKey1024) Encapsulator() *Encapsulator {
+	return ek.e
+}
+
+// NewDecapsulationKey1024FromStd converts a [crypto/mlkem.DecapsulationKey1024].
+func NewDecapsulationKey1024FromStd(dk *stdmlkem.DecapsulationKey1024) (*DecapsulationKey1024, error) {
+	return NewDecapsulationKey1024(dk.Bytes())
+}
+
+// Std converts the key to a [crypto/mlkem.DecapsulationKey1024].
+func (dk *DecapsulationKey1024) Std() (*stdmlkem.DecapsulationKey1024, error) {
+	return stdmlkem.NewDecapsulationKey1024(dk.Bytes())
+}
+
+// NewEncapsulationKey1024FromStd converts a [crypto/mlkem.EncapsulationKey1024].
+func NewEncapsulationKey1024FromStd(ek *stdmlkem.EncapsulationKey1024) (*EncapsulationKey1024, error) {
+	return NewEncapsulationKey1024(ek.Bytes())
+}
+
+// Std converts the key to a [crypto/mlkem.EncapsulationKey1024].
+func (ek *EncapsulationKey1024) Std() (*stdmlkem.EncapsulationKey1024, error) {
+	return stdmlkem.NewEncapsulationKey1024(ek.Bytes())
+}
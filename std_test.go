@@ -0,0 +1,157 @@
+package mlkem_test
+
+import (
+	"bytes"
+	stdmlkem "crypto/mlkem"
+	"testing"
+
+	"github.com/AlexanderYastrebov/mlkem"
+)
+
+type encapsulationKey interface {
+	Bytes() []byte
+	Encapsulate() (sharedKey, ciphertext []byte)
+}
+
+type decapsulationKey[E encapsulationKey] interface {
+	Bytes() []byte
+	EncapsulationKey() E
+	Decapsulate(ciphertext []byte) (sharedKey []byte, err error)
+}
+
+var (
+	_ decapsulationKey[*stdmlkem.EncapsulationKey768]  = (*stdmlkem.DecapsulationKey768)(nil)
+	_ decapsulationKey[*stdmlkem.EncapsulationKey1024] = (*stdmlkem.DecapsulationKey1024)(nil)
+	_ decapsulationKey[*mlkem.EncapsulationKey512]     = (*mlkem.DecapsulationKey512)(nil)
+	_ decapsulationKey[*mlkem.EncapsulationKey768]     = (*mlkem.DecapsulationKey768)(nil)
+	_ decapsulationKey[*mlkem.EncapsulationKey1024]    = (*mlkem.DecapsulationKey1024)(nil)
+)
+
+func testRoundTrip[E encapsulationKey](t *testing.T, dk decapsulationKey[E], ek encapsulationKey) {
+	t.Helper()
+
+	if !bytes.Equal(dk.EncapsulationKey().Bytes(), ek.Bytes()) {
+		t.Error("ek mismatch")
+	}
+
+	K1, c := ek.Encapsulate()
+	K2, err := dk.Decapsulate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(K1, K2) {
+		t.Errorf("%x != %x", K1, K2)
+	}
+}
+
+func TestStd512(t *testing.T) {
+	dk, err := mlkem.GenerateKey512()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, dk, dk.EncapsulationKey())
+
+	dk2, err := mlkem.NewDecapsulationKey512(dk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek2, err := mlkem.NewEncapsulationKey512(dk.EncapsulationKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, dk2, ek2)
+
+	if _, err := mlkem.NewDecapsulationKey512FromDecapsulator(dk.Decapsulator()); err != nil {
+		t.Error(err)
+	}
+	if _, err := mlkem.NewEncapsulationKey512FromEncapsulator(ek2.Encapsulator()); err != nil {
+		t.Error(err)
+	}
+
+	d768, err := mlkem.MLKEM_768.GenerateDecapsulator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mlkem.NewDecapsulationKey512FromDecapsulator(d768); err == nil {
+		t.Error("expected parameter set mismatch")
+	}
+	if _, err := mlkem.NewEncapsulationKey512FromEncapsulator(d768.EncapsulationKey()); err == nil {
+		t.Error("expected parameter set mismatch")
+	}
+}
+
+func TestStd768(t *testing.T) {
+	std, err := stdmlkem.GenerateKey768()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dk, err := mlkem.NewDecapsulationKey768FromStd(std)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(std.Bytes(), dk.Bytes()) {
+		t.Error("seed mismatch")
+	}
+	ek, err := mlkem.NewEncapsulationKey768FromStd(std.EncapsulationKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testRoundTrip(t, dk, ek)
+	testRoundTrip(t, std, ek)
+	testRoundTrip(t, dk, std.EncapsulationKey())
+
+	std2, err := dk.Std()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdek2, err := ek.Std()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, std2, stdek2)
+
+	dk2, err := mlkem.GenerateKey768()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, dk2, dk2.EncapsulationKey())
+}
+
+func TestStd1024(t *testing.T) {
+	std, err := stdmlkem.GenerateKey1024()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dk, err := mlkem.NewDecapsulationKey1024FromStd(std)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(std.Bytes(), dk.Bytes()) {
+		t.Error("seed mismatch")
+	}
+	ek, err := mlkem.NewEncapsulationKey1024FromStd(std.EncapsulationKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testRoundTrip(t, dk, ek)
+	testRoundTrip(t, std, ek)
+	testRoundTrip(t, dk, std.EncapsulationKey())
+
+	std2, err := dk.Std()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdek2, err := ek.Std()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, std2, stdek2)
+
+	dk2, err := mlkem.GenerateKey1024()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, dk2, dk2.EncapsulationKey())
+}